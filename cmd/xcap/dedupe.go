@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zn-chen/xcap/pkg/xcap"
+)
+
+// dedupeStateFile 保存每个显示器/窗口上一次已保存截图的哈希，位于输出目录下
+const dedupeStateFile = ".xcap_hashes.json"
+
+// dedupeTimeLayout 去重模式下文件名中的时间戳格式
+const dedupeTimeLayout = "20060102_150405"
+
+var (
+	dedupeThreshold int
+	dedupeAlgorithm string
+
+	// dedupe 为 nil 时不做去重（默认模式）
+	dedupe *dedupeState
+)
+
+// dedupeState 记录上一次保存的截图哈希
+type dedupeState struct {
+	path      string
+	threshold int
+	hash      func(image.Image) uint64
+	stamp     string            // 本次运行的时间戳，用于文件名
+	Hashes    map[string]uint64 `json:"hashes"`
+}
+
+func newDedupeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dedupe",
+		Short: "截图并跳过与上次保存的截图近似相同的显示器/窗口",
+		Long: "按显示器/窗口 ID 比较本次截图与上一次保存的截图的感知哈希，\n" +
+			"汉明距离不超过阈值时不保存。哈希记录保存在输出目录的 " + dedupeStateFile + " 中，\n" +
+			"保存的文件名包含 ID 和时间戳，不会覆盖之前的截图，适合由定时任务周期性调用。",
+		RunE: runDedupe,
+	}
+
+	cmd.Flags().IntVar(&dedupeThreshold, "threshold", 5, "汉明距离阈值（0-64），不超过该值视为重复")
+	cmd.Flags().StringVar(&dedupeAlgorithm, "algorithm", "phash", "哈希算法: ahash, dhash, phash")
+
+	return cmd
+}
+
+func runDedupe(cmd *cobra.Command, args []string) error {
+	var hash func(image.Image) uint64
+	switch dedupeAlgorithm {
+	case "ahash":
+		hash = xcap.AverageHash
+	case "dhash":
+		hash = xcap.DifferenceHash
+	case "phash":
+		hash = xcap.PerceptualHash
+	default:
+		return fmt.Errorf("unknown hash algorithm: %s", dedupeAlgorithm)
+	}
+
+	if dedupeThreshold < 0 || dedupeThreshold > 64 {
+		return fmt.Errorf("threshold must be between 0 and 64: %d", dedupeThreshold)
+	}
+
+	state, err := loadDedupeState(filepath.Join(defaultOutputDir, dedupeStateFile), dedupeThreshold, hash)
+	if err != nil {
+		return err
+	}
+
+	dedupe = state
+	runErr := run(cmd, args)
+
+	// 截图中途失败时也保存已记录的哈希
+	return errors.Join(runErr, state.save())
+}
+
+// loadDedupeState 读取哈希记录，文件不存在时返回空记录
+func loadDedupeState(path string, threshold int, hash func(image.Image) uint64) (*dedupeState, error) {
+	s := &dedupeState{
+		path:      path,
+		threshold: threshold,
+		hash:      hash,
+		stamp:     time.Now().Format(dedupeTimeLayout),
+		Hashes:    make(map[string]uint64),
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if s.Hashes == nil {
+		s.Hashes = make(map[string]uint64)
+	}
+
+	return s, nil
+}
+
+// skip 判断 key 对应的截图是否与上次保存的截图近似相同，返回截图的哈希和距离
+// 距离仅在跳过时有意义；不跳过时调用方在截图保存成功后用 record 记录哈希，
+// 保存失败的截图不会影响下一次比较。
+// 只与上次保存的截图比较，因此缓慢累积的变化最终仍会被保存
+func (s *dedupeState) skip(key string, img image.Image) (hash uint64, dist int, skipped bool) {
+	if s == nil {
+		return 0, 0, false
+	}
+
+	hash = s.hash(img)
+	if prev, ok := s.Hashes[key]; ok {
+		if dist := xcap.HammingDistance(prev, hash); dist <= s.threshold {
+			return hash, dist, true
+		}
+	}
+	return hash, 0, false
+}
+
+// record 记录 key 对应的截图已保存，hash 为 skip 返回的哈希
+func (s *dedupeState) record(key string, hash uint64) {
+	if s == nil {
+		return
+	}
+	s.Hashes[key] = hash
+}
+
+// filename 返回去重模式下的截图文件名
+// 默认模式的文件名每次运行都相同，新截图会覆盖旧文件，跳过保存也就节省不了空间；
+// 去重模式用稳定的显示器/窗口 ID 代替列表序号，并加上本次运行的时间戳，
+// 每次保存都是新文件，跳过的截图不会产生文件
+func (s *dedupeState) filename(prefix string, id uint32, name string) string {
+	return fmt.Sprintf("%s_%d_%s_%s.png", prefix, id, name, s.stamp)
+}
+
+// save 将哈希记录写回输出目录
+func (s *dedupeState) save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0644)
+}
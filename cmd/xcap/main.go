@@ -11,6 +11,9 @@ import (
 	"github.com/zn-chen/xcap/pkg/xcap"
 )
 
+// defaultOutputDir 截图输出目录
+const defaultOutputDir = "output"
+
 var (
 	version        = "dev"
 	disableMonitor bool
@@ -22,23 +25,32 @@ func main() {
 		Use:     "xcap",
 		Short:   "跨平台屏幕截图工具",
 		Version: version,
-		Run:     run,
+		RunE:    run,
 	}
 
-	rootCmd.Flags().BoolVar(&disableMonitor, "disable_monitor", false, "禁用显示器截图")
-	rootCmd.Flags().BoolVar(&disableWindows, "disable_windows", false, "禁用窗口截图")
+	rootCmd.PersistentFlags().BoolVar(&disableMonitor, "disable_monitor", false, "禁用显示器截图")
+	rootCmd.PersistentFlags().BoolVar(&disableWindows, "disable_windows", false, "禁用窗口截图")
+
+	addRedactFlags(rootCmd)
+
+	rootCmd.AddCommand(newDedupeCmd())
 
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
 	}
 }
 
-func run(cmd *cobra.Command, args []string) {
-	outputDir := "output"
+func run(cmd *cobra.Command, args []string) error {
+	outputDir := defaultOutputDir
+
+	r, err := newRedactor()
+	if err != nil {
+		return err
+	}
+	redactor = r
 
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		fmt.Fprintf(os.Stderr, "创建输出目录失败: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
 	var totalCaptured int
@@ -54,6 +66,7 @@ func run(cmd *cobra.Command, args []string) {
 	}
 
 	fmt.Printf("\n完成! 共截取 %d 张图片，保存到 %s/\n", totalCaptured, outputDir)
+	return nil
 }
 
 func captureMonitors(outputDir string) int {
@@ -67,19 +80,30 @@ func captureMonitors(outputDir string) int {
 
 	captured := 0
 	for i, m := range monitors {
-		img, err := m.CaptureImage()
+		img, err := captureMonitor(m)
 		if err != nil {
 			fmt.Printf("  显示器 %d: 截图失败 - %v\n", i+1, err)
 			continue
 		}
 
+		key := fmt.Sprintf("monitor:%d", m.ID())
+		hash, dist, skip := dedupe.skip(key, img)
+		if skip {
+			fmt.Printf("  显示器 %d: %s 与上次截图相似（距离 %d），跳过\n", i+1, m.Name(), dist)
+			continue
+		}
+
 		filename := fmt.Sprintf("monitor_%d_%s.png", i+1, xcap.SanitizeFilename(m.Name()))
+		if dedupe != nil {
+			filename = dedupe.filename("monitor", m.ID(), xcap.SanitizeFilename(m.Name()))
+		}
 		path := filepath.Join(outputDir, filename)
 
 		if err := saveImage(path, img); err != nil {
 			fmt.Printf("  显示器 %d: 保存失败 - %v\n", i+1, err)
 			continue
 		}
+		dedupe.record(key, hash)
 
 		fmt.Printf("  显示器 %d: %s -> %s\n", i+1, m.Name(), filename)
 		captured++
@@ -104,22 +128,39 @@ func captureWindows(outputDir string) int {
 			continue
 		}
 
+		if isRedacted(w) {
+			fmt.Printf("  窗口 %d: [%s] %s 命中脱敏规则，跳过\n", i+1, w.AppName(), w.Title())
+			continue
+		}
+
 		img, err := w.CaptureImage()
 		if err != nil {
 			continue
 		}
 
+		key := fmt.Sprintf("window:%d", w.ID())
+		hash, dist, skip := dedupe.skip(key, img)
+		if skip {
+			fmt.Printf("  窗口 %d: [%s] %s 与上次截图相似（距离 %d），跳过\n", i+1, w.AppName(), w.Title(), dist)
+			continue
+		}
+
 		title := w.Title()
 		if len(title) > 30 {
 			title = title[:30]
 		}
 
-		filename := fmt.Sprintf("window_%d_%s_%s.png", i+1, xcap.SanitizeFilename(w.AppName()), xcap.SanitizeFilename(title))
+		name := xcap.SanitizeFilename(w.AppName()) + "_" + xcap.SanitizeFilename(title)
+		filename := fmt.Sprintf("window_%d_%s.png", i+1, name)
+		if dedupe != nil {
+			filename = dedupe.filename("window", w.ID(), name)
+		}
 		path := filepath.Join(outputDir, filename)
 
 		if err := saveImage(path, img); err != nil {
 			continue
 		}
+		dedupe.record(key, hash)
 
 		// 获取焦点状态
 		focusedMark := ""
@@ -139,6 +180,10 @@ func saveImage(path string, img *image.RGBA) error {
 	if err != nil {
 		return err
 	}
-	defer f.Close()
-	return png.Encode(f, img)
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	// 关闭时才会报告部分写入错误，否则截图可能被当作已保存
+	return f.Close()
 }
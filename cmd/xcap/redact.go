@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"regexp"
+
+	"github.com/spf13/cobra"
+	"github.com/zn-chen/xcap/pkg/xcap"
+)
+
+var (
+	redactApps   []string
+	redactTitles []string
+	redactMode   string
+
+	// redactor 为 nil 时不做脱敏（默认模式）
+	redactor *xcap.Redactor
+)
+
+// addRedactFlags 注册脱敏相关的命令行参数，对所有子命令生效
+func addRedactFlags(cmd *cobra.Command) {
+	flags := cmd.PersistentFlags()
+	flags.StringSliceVar(&redactApps, "redact-app", nil, "遮盖指定应用的窗口（可重复，不区分大小写）")
+	flags.StringSliceVar(&redactTitles, "redact-title", nil, "遮盖标题匹配正则表达式的窗口（可重复）")
+	flags.StringVar(&redactMode, "redact-mode", "black", "遮盖方式: black, pixelate, blur")
+}
+
+// newRedactor 根据命令行参数创建 Redactor，未指定任何规则时返回 nil
+func newRedactor() (*xcap.Redactor, error) {
+	if len(redactApps) == 0 && len(redactTitles) == 0 {
+		return nil, nil
+	}
+
+	var mode xcap.RedactMode
+	switch redactMode {
+	case "black":
+		mode = xcap.RedactBlackout
+	case "pixelate":
+		mode = xcap.RedactPixelate
+	case "blur":
+		mode = xcap.RedactBlur
+	default:
+		return nil, fmt.Errorf("unknown redact mode: %s", redactMode)
+	}
+
+	r := &xcap.Redactor{}
+	for _, app := range redactApps {
+		r.Rules = append(r.Rules, xcap.RedactRule{AppName: app, Mode: mode})
+	}
+	for _, title := range redactTitles {
+		re, err := regexp.Compile(title)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact title pattern %q: %w", title, err)
+		}
+		r.Rules = append(r.Rules, xcap.RedactRule{Title: re, Mode: mode})
+	}
+
+	return r, nil
+}
+
+// captureMonitor 截取显示器，启用脱敏时先遮盖敏感窗口
+func captureMonitor(m xcap.Monitor) (*image.RGBA, error) {
+	if redactor == nil {
+		return m.CaptureImage()
+	}
+
+	img, redactions, err := redactor.CaptureMonitor(m)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range redactions {
+		fmt.Printf("    已遮盖 [%s] %s\n", r.Window.AppName(), r.Window.Title())
+	}
+	return img, nil
+}
+
+// isRedacted 判断窗口是否命中脱敏规则，命中的窗口不单独保存
+func isRedacted(w xcap.Window) bool {
+	if redactor == nil {
+		return false
+	}
+	for _, rule := range redactor.Rules {
+		if rule.MatchWindow(w) {
+			return true
+		}
+	}
+	return false
+}
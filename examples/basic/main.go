@@ -66,6 +66,10 @@ func demoMonitors(outputDir string) {
 		fmt.Printf("  Frequency:   %.0f Hz\n", m.Frequency())
 		fmt.Printf("  Primary:     %v\n", m.IsPrimary())
 		fmt.Printf("  Built-in:    %v\n", m.IsBuiltin())
+		if info, err := m.Info(); err == nil {
+			fmt.Printf("  Model:       %s %s (serial %q)\n", info.Manufacturer, info.Model, info.SerialNumber)
+			fmt.Printf("  Physical:    %d x %d mm, %s, %d-bit\n", info.WidthMM, info.HeightMM, info.Connector, info.ColorDepth)
+		}
 
 		// 截取屏幕
 		img, err := m.CaptureImage()
@@ -117,6 +121,15 @@ func demoWindows(outputDir string) {
 		fmt.Printf("  Position:    (%d, %d)\n", w.X(), w.Y())
 		fmt.Printf("  Size:        %d x %d\n", w.Width(), w.Height())
 		fmt.Printf("  Z-Order:     %d\n", w.Z())
+		if kind, err := w.Type(); err == nil {
+			fmt.Printf("  Type:        %s\n", kind)
+		}
+		if class, err := w.Class(); err == nil {
+			fmt.Printf("  Class:       %s\n", class)
+		}
+		if path, err := w.ProcessPath(); err == nil {
+			fmt.Printf("  Executable:  %s\n", path)
+		}
 		minimized, _ := w.IsMinimized()
 		maximized, _ := w.IsMaximized()
 		focused, _ := w.IsFocused()
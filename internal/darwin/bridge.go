@@ -4,15 +4,16 @@ package darwin
 
 /*
 #cgo CFLAGS: -x objective-c -Wno-deprecated-declarations -mmacosx-version-min=10.15
-#cgo LDFLAGS: -framework CoreGraphics -framework AppKit -framework CoreFoundation
+#cgo LDFLAGS: -framework CoreGraphics -framework AppKit -framework CoreFoundation -framework IOKit
 
 #include "bridge.h"
 #include <stdlib.h>
 */
 import "C"
 import (
-	"fmt"
 	"unsafe"
+
+	"github.com/zn-chen/xcap/internal/errcode"
 )
 
 // 错误码，与 bridge.h 中的定义对应
@@ -23,6 +24,20 @@ const (
 	errCaptureFailed = C.XCAP_ERR_CAPTURE_FAILED
 	errAllocFailed   = C.XCAP_ERR_ALLOC_FAILED
 	errNotFound      = C.XCAP_ERR_NOT_FOUND
+	errPermission    = C.XCAP_ERR_PERMISSION_DENIED
+	errMainThread    = C.XCAP_ERR_MAIN_THREAD_UNAVAILABLE
+)
+
+// 窗口类型，与 bridge.h 中的 XCAP_WINDOW_TYPE_* 对应
+const (
+	WindowTypeUnknown = C.XCAP_WINDOW_TYPE_UNKNOWN
+	WindowTypeNormal  = C.XCAP_WINDOW_TYPE_NORMAL
+	WindowTypeDialog  = C.XCAP_WINDOW_TYPE_DIALOG
+	WindowTypeUtility = C.XCAP_WINDOW_TYPE_UTILITY
+	WindowTypeMenu    = C.XCAP_WINDOW_TYPE_MENU
+	WindowTypeTooltip = C.XCAP_WINDOW_TYPE_TOOLTIP
+	WindowTypeDock    = C.XCAP_WINDOW_TYPE_DOCK
+	WindowTypeDesktop = C.XCAP_WINDOW_TYPE_DESKTOP
 )
 
 // MonitorInfo 表示从 C 层获取的显示器信息
@@ -35,6 +50,20 @@ type MonitorInfo struct {
 	Height      uint32
 	IsPrimary   bool
 	ScaleFactor float32
+	Rotation    float32
+	Frequency   float32
+	IsBuiltin   bool
+}
+
+// MonitorDetails 表示从 C 层获取的显示器硬件信息
+type MonitorDetails struct {
+	Vendor        uint32
+	Model         uint32
+	Serial        uint32
+	WidthMM       float32
+	HeightMM      float32
+	BitsPerSample int
+	EDID          []byte
 }
 
 // WindowInfo 表示从 C 层获取的窗口信息
@@ -49,6 +78,15 @@ type WindowInfo struct {
 	Height  uint32
 }
 
+// WindowDetails 表示按需查询的窗口元数据
+type WindowDetails struct {
+	Type        int // WindowType* 常量，根据窗口层级推断
+	Level       int32
+	Modal       bool
+	Opacity     float32
+	ProcessPath string // 无法读取时为空
+}
+
 // CaptureResult 表示从 C 层获取的原始截图数据
 type CaptureResult struct {
 	Data        []byte
@@ -57,6 +95,15 @@ type CaptureResult struct {
 	BytesPerRow uint32
 }
 
+// CursorResult 表示从 C 层获取的鼠标指针图像
+type CursorResult struct {
+	Image    *CaptureResult
+	HotspotX int32
+	HotspotY int32
+	Scale    float32
+	Visible  bool
+}
+
 // GetAllMonitors 返回所有活动显示器的信息
 func GetAllMonitors() ([]MonitorInfo, error) {
 	var cMonitors *C.XcapMonitorInfo
@@ -64,7 +111,7 @@ func GetAllMonitors() ([]MonitorInfo, error) {
 
 	result := C.xcap_get_all_monitors(&cMonitors, &cCount)
 	if result != errOK {
-		return nil, fmt.Errorf("failed to get monitors: error code %d", result)
+		return nil, &errcode.Error{Op: "get monitors", Code: errcode.Code(result)}
 	}
 	defer C.xcap_free_monitors(cMonitors)
 
@@ -89,12 +136,40 @@ func GetAllMonitors() ([]MonitorInfo, error) {
 			Height:      uint32(cSlice[i].height),
 			IsPrimary:   bool(cSlice[i].is_primary),
 			ScaleFactor: float32(cSlice[i].scale_factor),
+			Rotation:    float32(cSlice[i].rotation),
+			Frequency:   float32(cSlice[i].frequency),
+			IsBuiltin:   bool(cSlice[i].is_builtin),
 		}
 	}
 
 	return monitors, nil
 }
 
+// GetMonitorDetails 返回显示器的硬件信息，EDID 不可用时 EDID 为 nil
+func GetMonitorDetails(displayID uint32) (*MonitorDetails, error) {
+	var cDetails C.XcapMonitorDetails
+
+	result := C.xcap_get_monitor_details(C.uint32_t(displayID), &cDetails)
+	if result != errOK {
+		return nil, &errcode.Error{Op: "get monitor details", Code: errcode.Code(result)}
+	}
+	defer C.xcap_free_monitor_details(&cDetails)
+
+	details := &MonitorDetails{
+		Vendor:        uint32(cDetails.vendor),
+		Model:         uint32(cDetails.model),
+		Serial:        uint32(cDetails.serial),
+		WidthMM:       float32(cDetails.width_mm),
+		HeightMM:      float32(cDetails.height_mm),
+		BitsPerSample: int(cDetails.bits_per_sample),
+	}
+	if cDetails.edid != nil {
+		details.EDID = C.GoBytes(unsafe.Pointer(cDetails.edid), C.int(cDetails.edid_length))
+	}
+
+	return details, nil
+}
+
 // GetAllWindows 返回所有可见窗口的信息
 func GetAllWindows() ([]WindowInfo, error) {
 	return GetAllWindowsWithOptions(false)
@@ -108,7 +183,7 @@ func GetAllWindowsWithOptions(excludeCurrentProcess bool) ([]WindowInfo, error)
 
 	result := C.xcap_get_all_windows_ex(&cWindows, &cCount, C.bool(excludeCurrentProcess))
 	if result != errOK {
-		return nil, fmt.Errorf("failed to get windows: error code %d", result)
+		return nil, &errcode.Error{Op: "get windows", Code: errcode.Code(result)}
 	}
 	defer C.xcap_free_windows(cWindows)
 
@@ -124,21 +199,56 @@ func GetAllWindowsWithOptions(excludeCurrentProcess bool) ([]WindowInfo, error)
 	cSlice := unsafe.Slice(cWindows, count)
 
 	for i := 0; i < count; i++ {
-		windows[i] = WindowInfo{
-			ID:      uint32(cSlice[i].id),
-			PID:     uint32(cSlice[i].pid),
-			AppName: C.GoString(&cSlice[i].app_name[0]),
-			Title:   C.GoString(&cSlice[i].title[0]),
-			X:       int32(cSlice[i].x),
-			Y:       int32(cSlice[i].y),
-			Width:   uint32(cSlice[i].width),
-			Height:  uint32(cSlice[i].height),
-		}
+		windows[i] = windowInfoFromC(&cSlice[i])
 	}
 
 	return windows, nil
 }
 
+// windowInfoFromC 将 C 层的窗口信息转换为 WindowInfo
+func windowInfoFromC(c *C.XcapWindowInfo) WindowInfo {
+	return WindowInfo{
+		ID:      uint32(c.id),
+		PID:     uint32(c.pid),
+		AppName: C.GoString(&c.app_name[0]),
+		Title:   C.GoString(&c.title[0]),
+		X:       int32(c.x),
+		Y:       int32(c.y),
+		Width:   uint32(c.width),
+		Height:  uint32(c.height),
+	}
+}
+
+// GetWindowInfo 重新读取单个窗口的信息，窗口已关闭时返回 NotFound 错误
+func GetWindowInfo(windowID uint32) (WindowInfo, error) {
+	var cInfo C.XcapWindowInfo
+
+	result := C.xcap_get_window_info(C.uint32_t(windowID), &cInfo)
+	if result != errOK {
+		return WindowInfo{}, &errcode.Error{Op: "get window info", Code: errcode.Code(result)}
+	}
+
+	return windowInfoFromC(&cInfo), nil
+}
+
+// GetWindowDetails 读取窗口的层级、不透明度和进程路径，窗口已关闭时返回 NotFound 错误
+func GetWindowDetails(windowID uint32) (WindowDetails, error) {
+	var cDetails C.XcapWindowDetails
+
+	result := C.xcap_get_window_details(C.uint32_t(windowID), &cDetails)
+	if result != errOK {
+		return WindowDetails{}, &errcode.Error{Op: "get window details", Code: errcode.Code(result)}
+	}
+
+	return WindowDetails{
+		Type:        int(cDetails.window_type),
+		Level:       int32(cDetails.level),
+		Modal:       bool(cDetails.is_modal),
+		Opacity:     float32(cDetails.opacity),
+		ProcessPath: C.GoString(&cDetails.process_path[0]),
+	}, nil
+}
+
 // GetFrontmostWindowID 返回最前面窗口的 ID
 func GetFrontmostWindowID() uint32 {
 	return uint32(C.xcap_get_frontmost_window_id())
@@ -149,27 +259,86 @@ func GetCurrentPID() uint32 {
 	return uint32(C.xcap_get_current_pid())
 }
 
+// CheckScreenCaptureAccess 返回当前进程是否已获得屏幕录制权限，不会弹出提示
+func CheckScreenCaptureAccess() bool {
+	return bool(C.xcap_check_screen_capture_access())
+}
+
+// RequestScreenCaptureAccess 请求屏幕录制权限
+// 首次调用时系统会弹出授权提示；用户授权后通常需要重启进程才能生效
+func RequestScreenCaptureAccess() bool {
+	return bool(C.xcap_request_screen_capture_access())
+}
+
+// GetCursorPosition 返回鼠标指针的全局坐标（点，左上角为原点）
+func GetCursorPosition() (int32, int32, error) {
+	var x, y C.int32_t
+
+	result := C.xcap_get_cursor_position(&x, &y)
+	if result != errOK {
+		return 0, 0, &errcode.Error{Op: "get cursor position", Code: errcode.Code(result)}
+	}
+
+	return int32(x), int32(y), nil
+}
+
+// CaptureCursor 返回当前系统鼠标指针的图像和热点
+// NSCursor 只能在主线程上使用，主线程没有处理 main queue 时返回 ErrNotSupported
+func CaptureCursor() (*CursorResult, error) {
+	var cInfo C.XcapCursorInfo
+
+	result := C.xcap_capture_cursor(&cInfo)
+	if result == errMainThread {
+		return nil, ErrNotSupported
+	}
+	if result != errOK {
+		return nil, &errcode.Error{Op: "capture cursor", Code: errcode.Code(result)}
+	}
+	if !bool(cInfo.visible) {
+		return &CursorResult{}, nil
+	}
+	defer C.xcap_free_capture_result(&cInfo.image)
+
+	return &CursorResult{
+		Image:    copyCaptureResult(&cInfo.image),
+		HotspotX: int32(cInfo.hotspot_x),
+		HotspotY: int32(cInfo.hotspot_y),
+		Scale:    float32(cInfo.scale),
+		Visible:  true,
+	}, nil
+}
+
 // CaptureMonitor 截取指定显示器，返回原始 BGRA 数据
 func CaptureMonitor(displayID uint32) (*CaptureResult, error) {
 	var cResult C.XcapCaptureResult
 
 	result := C.xcap_capture_monitor(C.uint32_t(displayID), &cResult)
 	if result != errOK {
-		return nil, fmt.Errorf("failed to capture monitor: error code %d", result)
+		return nil, &errcode.Error{Op: "capture monitor", Code: errcode.Code(result)}
 	}
 	defer C.xcap_free_capture_result(&cResult)
 
-	// 将数据复制到 Go slice
-	dataLen := int(cResult.data_length)
-	data := make([]byte, dataLen)
-	copy(data, unsafe.Slice((*byte)(unsafe.Pointer(cResult.data)), dataLen))
+	return copyCaptureResult(&cResult), nil
+}
 
-	return &CaptureResult{
-		Data:        data,
-		Width:       uint32(cResult.width),
-		Height:      uint32(cResult.height),
-		BytesPerRow: uint32(cResult.bytes_per_row),
-	}, nil
+// CaptureMonitorRegion 截取显示器的指定区域（相对显示器左上角，单位为点），返回原始 BGRA 数据
+func CaptureMonitorRegion(displayID uint32, x, y int32, width, height uint32) (*CaptureResult, error) {
+	var cResult C.XcapCaptureResult
+
+	result := C.xcap_capture_monitor_region(
+		C.uint32_t(displayID),
+		C.int32_t(x),
+		C.int32_t(y),
+		C.uint32_t(width),
+		C.uint32_t(height),
+		&cResult,
+	)
+	if result != errOK {
+		return nil, &errcode.Error{Op: "capture monitor region", Code: errcode.Code(result)}
+	}
+	defer C.xcap_free_capture_result(&cResult)
+
+	return copyCaptureResult(&cResult), nil
 }
 
 // CaptureWindow 截取指定窗口，返回原始 BGRA 数据
@@ -178,11 +347,46 @@ func CaptureWindow(windowID uint32) (*CaptureResult, error) {
 
 	result := C.xcap_capture_window(C.uint32_t(windowID), &cResult)
 	if result != errOK {
-		return nil, fmt.Errorf("failed to capture window: error code %d", result)
+		return nil, &errcode.Error{Op: "capture window", Code: errcode.Code(result)}
 	}
 	defer C.xcap_free_capture_result(&cResult)
 
-	// 将数据复制到 Go slice
+	return copyCaptureResult(&cResult), nil
+}
+
+// CaptureWindowWithShadow 截取窗口及其阴影，图像大于窗口的位置和尺寸
+func CaptureWindowWithShadow(windowID uint32) (*CaptureResult, error) {
+	var cResult C.XcapCaptureResult
+
+	result := C.xcap_capture_window_ex(C.uint32_t(windowID), C.bool(true), &cResult)
+	if result != errOK {
+		return nil, &errcode.Error{Op: "capture window", Code: errcode.Code(result)}
+	}
+	defer C.xcap_free_capture_result(&cResult)
+
+	return copyCaptureResult(&cResult), nil
+}
+
+// CaptureMonitorExcluding 截取显示器，合成时跳过 excludeIDs 中的窗口
+func CaptureMonitorExcluding(displayID uint32, excludeIDs []uint32) (*CaptureResult, error) {
+	var cResult C.XcapCaptureResult
+
+	var ids *C.uint32_t
+	if len(excludeIDs) > 0 {
+		ids = (*C.uint32_t)(unsafe.Pointer(&excludeIDs[0]))
+	}
+
+	result := C.xcap_capture_monitor_excluding(C.uint32_t(displayID), ids, C.uint32_t(len(excludeIDs)), &cResult)
+	if result != errOK {
+		return nil, &errcode.Error{Op: "capture monitor excluding windows", Code: errcode.Code(result)}
+	}
+	defer C.xcap_free_capture_result(&cResult)
+
+	return copyCaptureResult(&cResult), nil
+}
+
+// copyCaptureResult 将 C 层的截图数据复制到 Go 内存
+func copyCaptureResult(cResult *C.XcapCaptureResult) *CaptureResult {
 	dataLen := int(cResult.data_length)
 	data := make([]byte, dataLen)
 	copy(data, unsafe.Slice((*byte)(unsafe.Pointer(cResult.data)), dataLen))
@@ -192,5 +396,5 @@ func CaptureWindow(windowID uint32) (*CaptureResult, error) {
 		Width:       uint32(cResult.width),
 		Height:      uint32(cResult.height),
 		BytesPerRow: uint32(cResult.bytes_per_row),
-	}, nil
+	}
 }
@@ -11,6 +11,18 @@
 #define XCAP_ERR_CAPTURE_FAILED 3
 #define XCAP_ERR_ALLOC_FAILED 4
 #define XCAP_ERR_NOT_FOUND 5
+#define XCAP_ERR_PERMISSION_DENIED 6
+#define XCAP_ERR_MAIN_THREAD_UNAVAILABLE 7  // The main queue did not run an AppKit call in time
+
+// Window types
+#define XCAP_WINDOW_TYPE_UNKNOWN 0
+#define XCAP_WINDOW_TYPE_NORMAL 1
+#define XCAP_WINDOW_TYPE_DIALOG 2
+#define XCAP_WINDOW_TYPE_UTILITY 3
+#define XCAP_WINDOW_TYPE_MENU 4
+#define XCAP_WINDOW_TYPE_TOOLTIP 5
+#define XCAP_WINDOW_TYPE_DOCK 6
+#define XCAP_WINDOW_TYPE_DESKTOP 7
 
 // Monitor information
 typedef struct {
@@ -22,8 +34,23 @@ typedef struct {
     uint32_t height;
     bool is_primary;
     float scale_factor;
+    float rotation;          // Clockwise degrees: 0, 90, 180 or 270
+    float frequency;         // Refresh rate in Hz, 0 if unknown
+    bool is_builtin;
 } XcapMonitorInfo;
 
+// Monitor hardware details for inventory
+typedef struct {
+    uint32_t vendor;         // EDID manufacturer code
+    uint32_t model;          // EDID product code
+    uint32_t serial;         // EDID serial number, 0 if none
+    float width_mm;
+    float height_mm;
+    uint32_t bits_per_sample;
+    uint8_t *edid;           // Raw EDID, NULL if unavailable
+    uint32_t edid_length;
+} XcapMonitorDetails;
+
 // Window information
 typedef struct {
     uint32_t id;
@@ -36,6 +63,15 @@ typedef struct {
     uint32_t height;
 } XcapWindowInfo;
 
+// Window metadata that is queried on demand rather than during enumeration
+typedef struct {
+    int32_t window_type;     // XCAP_WINDOW_TYPE_*, derived from the window level
+    int32_t level;           // kCGWindowLayer
+    bool is_modal;           // At the modal panel level
+    float opacity;           // kCGWindowAlpha, 0.0 - 1.0
+    char process_path[4096]; // Executable path (UTF-8), empty if not accessible
+} XcapWindowDetails;
+
 // Capture result
 typedef struct {
     uint8_t *data;
@@ -45,21 +81,47 @@ typedef struct {
     uint32_t data_length;
 } XcapCaptureResult;
 
+// Cursor image (BGRA pixel data) and hotspot
+typedef struct {
+    XcapCaptureResult image;
+    int32_t hotspot_x;       // Hotspot in image pixels
+    int32_t hotspot_y;
+    float scale;             // Image pixels per point
+    bool visible;
+} XcapCursorInfo;
+
 // Monitor functions
 int xcap_get_all_monitors(XcapMonitorInfo **monitors, int *count);
 void xcap_free_monitors(XcapMonitorInfo *monitors);
+int xcap_get_monitor_details(uint32_t display_id, XcapMonitorDetails *details);
+void xcap_free_monitor_details(XcapMonitorDetails *details);
 int xcap_capture_monitor(uint32_t display_id, XcapCaptureResult *result);
+int xcap_capture_monitor_region(uint32_t display_id, int32_t x, int32_t y,
+                                uint32_t width, uint32_t height, XcapCaptureResult *result);
+int xcap_capture_monitor_excluding(uint32_t display_id, const uint32_t *exclude_ids,
+                                   uint32_t exclude_count, XcapCaptureResult *result);
 
 // Window functions
 int xcap_get_all_windows(XcapWindowInfo **windows, int *count);
 int xcap_get_all_windows_ex(XcapWindowInfo **windows, int *count, bool exclude_current_process);
 void xcap_free_windows(XcapWindowInfo *windows);
+int xcap_get_window_info(uint32_t window_id, XcapWindowInfo *info);
+int xcap_get_window_details(uint32_t window_id, XcapWindowDetails *details);
 int xcap_capture_window(uint32_t window_id, XcapCaptureResult *result);
+int xcap_capture_window_ex(uint32_t window_id, bool include_shadow, XcapCaptureResult *result);
 
 // Window state functions
 uint32_t xcap_get_frontmost_window_id(void);
 uint32_t xcap_get_current_pid(void);
 
+// Cursor functions
+int xcap_get_cursor_position(int32_t *x, int32_t *y);
+int xcap_capture_cursor(XcapCursorInfo *info);
+
+// Permission functions
+bool xcap_check_screen_capture_access(void);
+bool xcap_request_screen_capture_access(void);
+
 // Capture cleanup
 void xcap_free_capture_result(XcapCaptureResult *result);
 
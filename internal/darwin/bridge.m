@@ -1,6 +1,11 @@
 #import <Foundation/Foundation.h>
 #import <AppKit/AppKit.h>
 #import <CoreGraphics/CoreGraphics.h>
+#include <IOKit/IOKitLib.h>
+#include <IOKit/graphics/IOGraphicsLib.h>
+#include <libproc.h>
+#include <math.h>
+#include <stdatomic.h>
 #include <stdlib.h>
 #include <string.h>
 #include "bridge.h"
@@ -20,8 +25,192 @@ static void copy_nsstring_to_buffer(NSString *str, char *buffer, size_t buffer_s
     }
 }
 
+// Helper: Render CGImage into a tightly packed sRGB BGRA buffer
+// Drawing through an sRGB bitmap context converts from the display color space
+static int copy_image_to_result(CGImageRef image, XcapCaptureResult *result) {
+    size_t width = CGImageGetWidth(image);
+    size_t height = CGImageGetHeight(image);
+    size_t bytes_per_row = width * 4;
+    size_t data_length = bytes_per_row * height;
+
+    uint8_t *data = (uint8_t *)calloc(data_length, 1);
+    if (data == NULL) {
+        return XCAP_ERR_ALLOC_FAILED;
+    }
+
+    CGColorSpaceRef srgb = CGColorSpaceCreateWithName(kCGColorSpaceSRGB);
+    CGContextRef ctx = CGBitmapContextCreate(
+        data, width, height, 8, bytes_per_row, srgb,
+        kCGImageAlphaPremultipliedFirst | kCGBitmapByteOrder32Little
+    );
+    CGColorSpaceRelease(srgb);
+
+    if (ctx == NULL) {
+        free(data);
+        return XCAP_ERR_CAPTURE_FAILED;
+    }
+
+    CGContextDrawImage(ctx, CGRectMake(0, 0, width, height), image);
+    CGContextRelease(ctx);
+
+    result->data = data;
+    result->width = (uint32_t)width;
+    result->height = (uint32_t)height;
+    result->bytes_per_row = (uint32_t)bytes_per_row;
+    result->data_length = (uint32_t)data_length;
+
+    return XCAP_OK;
+}
+
 #pragma mark - Monitor Functions
 
+// Helper: AppKit calls on the main thread
+//
+// Callers run on the xcap worker thread, but NSScreen and NSCursor must be used on the
+// main thread. The work is dispatched to the main queue and the caller waits a bounded
+// time: a Go program's main thread usually does not service the main queue, so a
+// blocking dispatch_sync could hang forever. While an earlier call is still queued the
+// main queue is treated as unavailable and callers fall back without waiting again.
+#define MAIN_THREAD_TIMEOUT_MS 250
+
+typedef struct MainCall {
+    atomic_int refs;
+    void (*run)(struct MainCall *call);
+    void (*cleanup)(struct MainCall *call); // Frees results the caller did not take, may be NULL
+} MainCall;
+
+// Calls dispatched to the main queue that have not started yet
+static atomic_int main_calls_pending;
+
+static void main_call_release(MainCall *call) {
+    if (atomic_fetch_sub(&call->refs, 1) == 1) {
+        if (call->cleanup != NULL) {
+            call->cleanup(call);
+        }
+        free(call);
+    }
+}
+
+// Runs call on the main thread. Returns false if the main queue did not run it in time;
+// the call may still run later, so results are only valid when true is returned.
+// Either way the caller releases its reference with main_call_release, after reading the results.
+static bool run_on_main_thread(MainCall *call) {
+    if ([NSThread isMainThread]) {
+        atomic_store(&call->refs, 1);
+        call->run(call);
+        return true;
+    }
+    if (atomic_load(&main_calls_pending) > 0) {
+        atomic_store(&call->refs, 1);
+        return false;
+    }
+
+    atomic_store(&call->refs, 2);
+    atomic_fetch_add(&main_calls_pending, 1);
+    dispatch_semaphore_t done = dispatch_semaphore_create(0);
+    dispatch_async(dispatch_get_main_queue(), ^{
+        atomic_fetch_sub(&main_calls_pending, 1);
+        call->run(call);
+        dispatch_semaphore_signal(done);
+        main_call_release(call);
+    });
+
+    long timed_out = dispatch_semaphore_wait(done,
+        dispatch_time(DISPATCH_TIME_NOW, (int64_t)MAIN_THREAD_TIMEOUT_MS * NSEC_PER_MSEC));
+    dispatch_release(done);
+    return timed_out == 0;
+}
+
+// Helper: NSScreen for a display, nil if the display has no screen. Main thread only.
+static NSScreen *screen_for_display(CGDirectDisplayID display_id) {
+    for (NSScreen *screen in [NSScreen screens]) {
+        NSNumber *screenNumber = [screen deviceDescription][@"NSScreenNumber"];
+        if (screenNumber && [screenNumber unsignedIntValue] == display_id) {
+            return screen;
+        }
+    }
+    return nil;
+}
+
+// NSScreen properties of up to 16 displays, read on the main thread
+typedef struct {
+    MainCall call;
+    uint32_t count;
+    CGDirectDisplayID ids[16];
+    struct {
+        bool found;
+        char name[256];
+        float max_fps;           // 0 if unknown
+        uint32_t bits_per_sample;
+    } screens[16];
+} ScreenCall;
+
+static void screen_call_run(MainCall *call) {
+    @autoreleasepool {
+        ScreenCall *sc = (ScreenCall *)call;
+        for (uint32_t i = 0; i < sc->count; i++) {
+            NSScreen *screen = screen_for_display(sc->ids[i]);
+            if (screen == nil) {
+                continue;
+            }
+            sc->screens[i].found = true;
+            copy_nsstring_to_buffer([screen localizedName], sc->screens[i].name, sizeof(sc->screens[i].name));
+            if (@available(macOS 12.0, *)) {
+                sc->screens[i].max_fps = (float)[screen maximumFramesPerSecond];
+            }
+            sc->screens[i].bits_per_sample = (uint32_t)NSBitsPerSampleFromDepth([screen depth]);
+        }
+    }
+}
+
+// Looks up the NSScreen properties of the given displays, NULL if the main thread did not
+// answer. The result must be released with main_call_release.
+static ScreenCall *query_screens(const CGDirectDisplayID *ids, uint32_t count) {
+    ScreenCall *sc = (ScreenCall *)calloc(1, sizeof(ScreenCall));
+    if (sc == NULL) {
+        return NULL;
+    }
+    sc->call.run = screen_call_run;
+    sc->count = count < 16 ? count : 16;
+    memcpy(sc->ids, ids, sc->count * sizeof(CGDirectDisplayID));
+
+    if (!run_on_main_thread(&sc->call)) {
+        main_call_release(&sc->call);
+        return NULL;
+    }
+    return sc;
+}
+
+// Helper: Backing scale factor from the current display mode
+static float display_scale_factor(CGDirectDisplayID display_id) {
+    float scale = 1.0f;
+    CGDisplayModeRef mode = CGDisplayCopyDisplayMode(display_id);
+    if (mode != NULL) {
+        size_t width = CGDisplayModeGetWidth(mode);
+        if (width > 0) {
+            scale = (float)CGDisplayModeGetPixelWidth(mode) / (float)width;
+        }
+        CGDisplayModeRelease(mode);
+    }
+    return scale;
+}
+
+// Helper: Refresh rate of the current display mode
+static float display_refresh_rate(CGDirectDisplayID display_id, float screen_max_fps) {
+    double rate = 0;
+    CGDisplayModeRef mode = CGDisplayCopyDisplayMode(display_id);
+    if (mode != NULL) {
+        rate = CGDisplayModeGetRefreshRate(mode);
+        CGDisplayModeRelease(mode);
+    }
+
+    // Built-in panels report 0 from the display mode
+    if (rate <= 0) {
+        rate = screen_max_fps;
+    }
+    return (float)rate;
+}
+
 int xcap_get_all_monitors(XcapMonitorInfo **monitors, int *count) {
     @autoreleasepool {
         // Get active display list
@@ -44,8 +233,8 @@ int xcap_get_all_monitors(XcapMonitorInfo **monitors, int *count) {
             return XCAP_ERR_ALLOC_FAILED;
         }
 
-        // Get NSScreen array for friendly names
-        NSArray<NSScreen *> *screens = [NSScreen screens];
+        // Friendly names and the refresh rate of built-in panels come from NSScreen
+        ScreenCall *screens = query_screens(display_ids, display_count);
 
         for (uint32_t i = 0; i < display_count; i++) {
             CGDirectDisplayID display_id = display_ids[i];
@@ -57,27 +246,23 @@ int xcap_get_all_monitors(XcapMonitorInfo **monitors, int *count) {
             result[i].width = (uint32_t)bounds.size.width;
             result[i].height = (uint32_t)bounds.size.height;
             result[i].is_primary = CGDisplayIsMain(display_id);
+            result[i].rotation = (float)CGDisplayRotation(display_id);
 
-            // Find matching NSScreen for friendly name and scale factor
-            NSString *name = nil;
-            CGFloat scale = 1.0;
-            for (NSScreen *screen in screens) {
-                NSDictionary *desc = [screen deviceDescription];
-                NSNumber *screenNumber = desc[@"NSScreenNumber"];
-                if (screenNumber && [screenNumber unsignedIntValue] == display_id) {
-                    name = [screen localizedName];
-                    scale = [screen backingScaleFactor];
-                    break;
-                }
-            }
-            result[i].scale_factor = (float)scale;
+            result[i].is_builtin = CGDisplayIsBuiltin(display_id);
 
-            if (name) {
-                copy_nsstring_to_buffer(name, result[i].name, sizeof(result[i].name));
+            bool has_screen = screens != NULL && screens->screens[i].found;
+            result[i].scale_factor = display_scale_factor(display_id);
+            result[i].frequency = display_refresh_rate(display_id, has_screen ? screens->screens[i].max_fps : 0);
+
+            if (has_screen && screens->screens[i].name[0] != '\0') {
+                memcpy(result[i].name, screens->screens[i].name, sizeof(result[i].name));
             } else {
                 snprintf(result[i].name, sizeof(result[i].name), "Display %u", display_id);
             }
         }
+        if (screens != NULL) {
+            main_call_release(&screens->call);
+        }
 
         *monitors = result;
         *count = (int)display_count;
@@ -91,6 +276,87 @@ void xcap_free_monitors(XcapMonitorInfo *monitors) {
     }
 }
 
+// Helper: Integer value of a CFNumber dictionary entry, 0 if missing
+static uint32_t dictionary_uint32(CFDictionaryRef dict, CFStringRef key) {
+    CFTypeRef value = CFDictionaryGetValue(dict, key);
+    int64_t n = 0;
+    if (value != NULL && CFGetTypeID(value) == CFNumberGetTypeID()) {
+        CFNumberGetValue((CFNumberRef)value, kCFNumberSInt64Type, &n);
+    }
+    return (uint32_t)n;
+}
+
+// Helper: Copy EDID from the IODisplayConnect service whose vendor, product
+// and serial numbers match the display. Apple Silicon Macs have no
+// IODisplayConnect services, so EDID is only available on Intel Macs.
+static void copy_display_edid(XcapMonitorDetails *details) {
+    io_iterator_t iter;
+    if (IOServiceGetMatchingServices(kIOMasterPortDefault, IOServiceMatching("IODisplayConnect"), &iter) != KERN_SUCCESS) {
+        return;
+    }
+
+    io_service_t service;
+    while (details->edid == NULL && (service = IOIteratorNext(iter)) != 0) {
+        CFDictionaryRef info = IODisplayCreateInfoDictionary(service, kIODisplayOnlyPreferredName);
+        IOObjectRelease(service);
+        if (info == NULL) {
+            continue;
+        }
+
+        uint32_t serial = dictionary_uint32(info, CFSTR(kDisplaySerialNumber));
+        if (dictionary_uint32(info, CFSTR(kDisplayVendorID)) == details->vendor &&
+            dictionary_uint32(info, CFSTR(kDisplayProductID)) == details->model &&
+            (details->serial == 0 || serial == details->serial)) {
+            CFTypeRef edid = CFDictionaryGetValue(info, CFSTR(kIODisplayEDIDKey));
+            if (edid != NULL && CFGetTypeID(edid) == CFDataGetTypeID()) {
+                CFIndex length = CFDataGetLength((CFDataRef)edid);
+                uint8_t *data = (uint8_t *)malloc((size_t)length);
+                if (data != NULL) {
+                    CFDataGetBytes((CFDataRef)edid, CFRangeMake(0, length), data);
+                    details->edid = data;
+                    details->edid_length = (uint32_t)length;
+                }
+            }
+        }
+        CFRelease(info);
+    }
+    IOObjectRelease(iter);
+}
+
+int xcap_get_monitor_details(uint32_t display_id, XcapMonitorDetails *details) {
+    @autoreleasepool {
+        memset(details, 0, sizeof(*details));
+
+        details->vendor = CGDisplayVendorNumber(display_id);
+        details->model = CGDisplayModelNumber(display_id);
+        details->serial = CGDisplaySerialNumber(display_id);
+
+        CGSize size = CGDisplayScreenSize(display_id);
+        details->width_mm = (float)size.width;
+        details->height_mm = (float)size.height;
+
+        // 0 if the main thread did not answer
+        CGDirectDisplayID ids[1] = {display_id};
+        ScreenCall *screens = query_screens(ids, 1);
+        if (screens != NULL) {
+            if (screens->screens[0].found) {
+                details->bits_per_sample = screens->screens[0].bits_per_sample;
+            }
+            main_call_release(&screens->call);
+        }
+
+        copy_display_edid(details);
+        return XCAP_OK;
+    }
+}
+
+void xcap_free_monitor_details(XcapMonitorDetails *details) {
+    if (details && details->edid) {
+        free(details->edid);
+        details->edid = NULL;
+    }
+}
+
 int xcap_capture_monitor(uint32_t display_id, XcapCaptureResult *result) {
     @autoreleasepool {
         // Get display bounds
@@ -108,46 +374,155 @@ int xcap_capture_monitor(uint32_t display_id, XcapCaptureResult *result) {
             return XCAP_ERR_CAPTURE_FAILED;
         }
 
-        // Get image properties
-        size_t width = CGImageGetWidth(image);
-        size_t height = CGImageGetHeight(image);
-        size_t bytes_per_row = CGImageGetBytesPerRow(image);
+        // Copy pixel data as sRGB BGRA
+        int ret = copy_image_to_result(image, result);
+        CGImageRelease(image);
 
-        // Get pixel data
-        CGDataProviderRef provider = CGImageGetDataProvider(image);
-        CFDataRef data = CGDataProviderCopyData(provider);
+        return ret;
+    }
+}
 
-        if (data == NULL) {
-            CGImageRelease(image);
+int xcap_capture_monitor_region(uint32_t display_id, int32_t x, int32_t y,
+                                uint32_t width, uint32_t height, XcapCaptureResult *result) {
+    @autoreleasepool {
+        // Region is relative to the display origin, in points
+        CGRect bounds = CGDisplayBounds(display_id);
+        CGRect region = CGRectMake(bounds.origin.x + x, bounds.origin.y + y, width, height);
+
+        if (width == 0 || height == 0 || !CGRectContainsRect(bounds, region)) {
             return XCAP_ERR_CAPTURE_FAILED;
         }
 
-        const uint8_t *src = CFDataGetBytePtr(data);
-        size_t data_length = CFDataGetLength(data);
+        CGImageRef image = CGWindowListCreateImage(
+            region,
+            kCGWindowListOptionAll,
+            kCGNullWindowID,
+            kCGWindowImageDefault
+        );
 
-        // Allocate and copy data
-        result->data = (uint8_t *)malloc(data_length);
-        if (result->data == NULL) {
-            CFRelease(data);
-            CGImageRelease(image);
+        if (image == NULL) {
+            return XCAP_ERR_CAPTURE_FAILED;
+        }
+
+        int ret = copy_image_to_result(image, result);
+        CGImageRelease(image);
+
+        return ret;
+    }
+}
+
+int xcap_capture_monitor_excluding(uint32_t display_id, const uint32_t *exclude_ids,
+                                   uint32_t exclude_count, XcapCaptureResult *result) {
+    @autoreleasepool {
+        CGRect bounds = CGDisplayBounds(display_id);
+
+        // Include desktop elements so the wallpaper shows through excluded windows
+        CFArrayRef window_list = CGWindowListCopyWindowInfo(
+            kCGWindowListOptionOnScreenOnly,
+            kCGNullWindowID
+        );
+        if (window_list == NULL) {
+            return XCAP_ERR_CAPTURE_FAILED;
+        }
+
+        CFIndex window_count = CFArrayGetCount(window_list);
+        const void **ids = malloc(sizeof(void *) * (window_count > 0 ? window_count : 1));
+        if (ids == NULL) {
+            CFRelease(window_list);
+            return XCAP_ERR_ALLOC_FAILED;
+        }
+
+        // Keep the front-to-back order of the window list
+        CFIndex kept = 0;
+        for (CFIndex i = 0; i < window_count; i++) {
+            CFDictionaryRef window_info = CFArrayGetValueAtIndex(window_list, i);
+            CFNumberRef id_ref = CFDictionaryGetValue(window_info, kCGWindowNumber);
+            uint32_t window_id = 0;
+            if (id_ref == NULL || !CFNumberGetValue(id_ref, kCFNumberSInt32Type, &window_id)) {
+                continue;
+            }
+
+            bool excluded = false;
+            for (uint32_t j = 0; j < exclude_count; j++) {
+                if (exclude_ids[j] == window_id) {
+                    excluded = true;
+                    break;
+                }
+            }
+            if (!excluded) {
+                ids[kept++] = (const void *)(uintptr_t)window_id;
+            }
+        }
+        CFRelease(window_list);
+
+        // The array holds raw CGWindowID values, not CF objects
+        CFArrayRef window_array = CFArrayCreate(kCFAllocatorDefault, ids, kept, NULL);
+        free(ids);
+        if (window_array == NULL) {
             return XCAP_ERR_ALLOC_FAILED;
         }
 
-        memcpy(result->data, src, data_length);
-        result->width = (uint32_t)width;
-        result->height = (uint32_t)height;
-        result->bytes_per_row = (uint32_t)bytes_per_row;
-        result->data_length = (uint32_t)data_length;
+        CGImageRef image = CGWindowListCreateImageFromArray(bounds, window_array, kCGWindowImageDefault);
+        CFRelease(window_array);
 
-        CFRelease(data);
+        if (image == NULL) {
+            return XCAP_ERR_CAPTURE_FAILED;
+        }
+
+        int ret = copy_image_to_result(image, result);
         CGImageRelease(image);
 
-        return XCAP_OK;
+        return ret;
     }
 }
 
 #pragma mark - Window Functions
 
+// Helper: Fill XcapWindowInfo from a CGWindowList dictionary
+static void fill_window_info(CFDictionaryRef window_info, XcapWindowInfo *info) {
+    // Get window ID
+    CFNumberRef window_id_ref = CFDictionaryGetValue(window_info, kCGWindowNumber);
+    uint32_t window_id = 0;
+    if (window_id_ref) {
+        CFNumberGetValue(window_id_ref, kCFNumberIntType, &window_id);
+    }
+
+    // Get PID
+    CFNumberRef pid_ref = CFDictionaryGetValue(window_info, kCGWindowOwnerPID);
+    uint32_t pid = 0;
+    if (pid_ref) {
+        CFNumberGetValue(pid_ref, kCFNumberIntType, &pid);
+    }
+
+    // Get bounds
+    CFDictionaryRef bounds_ref = CFDictionaryGetValue(window_info, kCGWindowBounds);
+    CGRect bounds = CGRectZero;
+    if (bounds_ref) {
+        CGRectMakeWithDictionaryRepresentation(bounds_ref, &bounds);
+    }
+
+    info->id = window_id;
+    info->pid = pid;
+    info->x = (int32_t)bounds.origin.x;
+    info->y = (int32_t)bounds.origin.y;
+    info->width = (uint32_t)bounds.size.width;
+    info->height = (uint32_t)bounds.size.height;
+
+    // Get app name
+    CFStringRef owner_ref = CFDictionaryGetValue(window_info, kCGWindowOwnerName);
+    if (owner_ref) {
+        NSString *owner = (__bridge NSString *)owner_ref;
+        copy_nsstring_to_buffer(owner, info->app_name, sizeof(info->app_name));
+    }
+
+    // Get window title
+    CFStringRef name_ref = CFDictionaryGetValue(window_info, kCGWindowName);
+    if (name_ref) {
+        NSString *name = (__bridge NSString *)name_ref;
+        copy_nsstring_to_buffer(name, info->title, sizeof(info->title));
+    }
+}
+
 // Internal implementation with exclude option
 static int xcap_get_all_windows_internal(XcapWindowInfo **windows, int *count, bool exclude_current_process, pid_t current_pid) {
     @autoreleasepool {
@@ -266,39 +641,7 @@ static int xcap_get_all_windows_internal(XcapWindowInfo **windows, int *count, b
                 }
             }
 
-            // Get window ID
-            CFNumberRef window_id_ref = CFDictionaryGetValue(window_info, kCGWindowNumber);
-            uint32_t window_id = 0;
-            if (window_id_ref) {
-                CFNumberGetValue(window_id_ref, kCFNumberIntType, &window_id);
-            }
-
-            // Get bounds
-            CFDictionaryRef bounds_ref = CFDictionaryGetValue(window_info, kCGWindowBounds);
-            CGRect bounds = CGRectZero;
-            if (bounds_ref) {
-                CGRectMakeWithDictionaryRepresentation(bounds_ref, &bounds);
-            }
-
-            result[result_index].id = window_id;
-            result[result_index].pid = pid;
-            result[result_index].x = (int32_t)bounds.origin.x;
-            result[result_index].y = (int32_t)bounds.origin.y;
-            result[result_index].width = (uint32_t)bounds.size.width;
-            result[result_index].height = (uint32_t)bounds.size.height;
-
-            // Get app name
-            if (owner_ref) {
-                NSString *owner = (__bridge NSString *)owner_ref;
-                copy_nsstring_to_buffer(owner, result[result_index].app_name, sizeof(result[result_index].app_name));
-            }
-
-            // Get window title
-            if (name_ref) {
-                NSString *name = (__bridge NSString *)name_ref;
-                copy_nsstring_to_buffer(name, result[result_index].title, sizeof(result[result_index].title));
-            }
-
+            fill_window_info(window_info, &result[result_index]);
             result_index++;
         }
 
@@ -319,6 +662,107 @@ int xcap_get_all_windows_ex(XcapWindowInfo **windows, int *count, bool exclude_c
     return xcap_get_all_windows_internal(windows, count, exclude_current_process, current_pid);
 }
 
+// Live metadata of a single window, including windows that are off screen
+int xcap_get_window_info(uint32_t window_id, XcapWindowInfo *info) {
+    @autoreleasepool {
+        memset(info, 0, sizeof(*info));
+
+        CFArrayRef window_list = CGWindowListCopyWindowInfo(
+            kCGWindowListOptionIncludingWindow,
+            window_id
+        );
+
+        if (window_list == NULL || CFArrayGetCount(window_list) == 0) {
+            if (window_list) CFRelease(window_list);
+            return XCAP_ERR_NOT_FOUND;
+        }
+
+        fill_window_info(CFArrayGetValueAtIndex(window_list, 0), info);
+        CFRelease(window_list);
+        return XCAP_OK;
+    }
+}
+
+// Map a window level to a window type
+// Levels are compared through CGWindowLevelForKey, several keys share a value
+static int32_t window_type_for_level(int32_t level) {
+    if (level == CGWindowLevelForKey(kCGNormalWindowLevelKey)) {
+        return XCAP_WINDOW_TYPE_NORMAL;
+    }
+    if (level == CGWindowLevelForKey(kCGModalPanelWindowLevelKey)) {
+        return XCAP_WINDOW_TYPE_DIALOG;
+    }
+    if (level == CGWindowLevelForKey(kCGFloatingWindowLevelKey) ||
+        level == CGWindowLevelForKey(kCGUtilityWindowLevelKey)) {
+        return XCAP_WINDOW_TYPE_UTILITY;
+    }
+    if (level == CGWindowLevelForKey(kCGPopUpMenuWindowLevelKey)) {
+        return XCAP_WINDOW_TYPE_MENU;
+    }
+    if (level == CGWindowLevelForKey(kCGHelpWindowLevelKey)) {
+        return XCAP_WINDOW_TYPE_TOOLTIP;
+    }
+    if (level == CGWindowLevelForKey(kCGDockWindowLevelKey) ||
+        level == CGWindowLevelForKey(kCGMainMenuWindowLevelKey) ||
+        level == CGWindowLevelForKey(kCGStatusWindowLevelKey)) {
+        return XCAP_WINDOW_TYPE_DOCK;
+    }
+    if (level == CGWindowLevelForKey(kCGDesktopWindowLevelKey) ||
+        level == CGWindowLevelForKey(kCGDesktopIconWindowLevelKey)) {
+        return XCAP_WINDOW_TYPE_DESKTOP;
+    }
+    return XCAP_WINDOW_TYPE_UNKNOWN;
+}
+
+int xcap_get_window_details(uint32_t window_id, XcapWindowDetails *details) {
+    @autoreleasepool {
+        memset(details, 0, sizeof(*details));
+
+        CFArrayRef window_list = CGWindowListCopyWindowInfo(
+            kCGWindowListOptionIncludingWindow,
+            window_id
+        );
+
+        if (window_list == NULL || CFArrayGetCount(window_list) == 0) {
+            if (window_list) CFRelease(window_list);
+            return XCAP_ERR_NOT_FOUND;
+        }
+
+        CFDictionaryRef window_info = CFArrayGetValueAtIndex(window_list, 0);
+
+        int32_t level = 0;
+        CFNumberRef layer_ref = CFDictionaryGetValue(window_info, kCGWindowLayer);
+        if (layer_ref) {
+            CFNumberGetValue(layer_ref, kCFNumberSInt32Type, &level);
+        }
+        details->level = level;
+        details->window_type = window_type_for_level(level);
+        details->is_modal = level == CGWindowLevelForKey(kCGModalPanelWindowLevelKey);
+
+        double alpha = 1.0;
+        CFNumberRef alpha_ref = CFDictionaryGetValue(window_info, kCGWindowAlpha);
+        if (alpha_ref) {
+            CFNumberGetValue(alpha_ref, kCFNumberDoubleType, &alpha);
+        }
+        details->opacity = (float)alpha;
+
+        int pid = 0;
+        CFNumberRef pid_ref = CFDictionaryGetValue(window_info, kCGWindowOwnerPID);
+        if (pid_ref) {
+            CFNumberGetValue(pid_ref, kCFNumberIntType, &pid);
+        }
+
+        CFRelease(window_list);
+
+        // proc_pidpath fails if the process has exited or the caller is sandboxed
+        if (pid > 0 && proc_pidpath(pid, details->process_path, sizeof(details->process_path)) <= 0) {
+            details->process_path[0] = '\0';
+        }
+
+        return XCAP_OK;
+    }
+}
+
 void xcap_free_windows(XcapWindowInfo *windows) {
     if (windows) {
         free(windows);
@@ -326,6 +770,12 @@ void xcap_free_windows(XcapWindowInfo *windows) {
 }
 
 int xcap_capture_window(uint32_t window_id, XcapCaptureResult *result) {
+    return xcap_capture_window_ex(window_id, false, result);
+}
+
+// With include_shadow the image is not clipped to the window bounds, so the
+// window server composites the drop shadow around the frame
+int xcap_capture_window_ex(uint32_t window_id, bool include_shadow, XcapCaptureResult *result) {
     @autoreleasepool {
         // Get window bounds first
         CFArrayRef window_list = CGWindowListCopyWindowInfo(
@@ -349,7 +799,7 @@ int xcap_capture_window(uint32_t window_id, XcapCaptureResult *result) {
 
         // Capture the window
         CGImageRef image = CGWindowListCreateImage(
-            bounds,
+            include_shadow ? CGRectNull : bounds,
             kCGWindowListOptionIncludingWindow,
             window_id,
             kCGWindowImageDefault
@@ -359,41 +809,11 @@ int xcap_capture_window(uint32_t window_id, XcapCaptureResult *result) {
             return XCAP_ERR_CAPTURE_FAILED;
         }
 
-        // Get image properties
-        size_t width = CGImageGetWidth(image);
-        size_t height = CGImageGetHeight(image);
-        size_t bytes_per_row = CGImageGetBytesPerRow(image);
-
-        // Get pixel data
-        CGDataProviderRef provider = CGImageGetDataProvider(image);
-        CFDataRef data = CGDataProviderCopyData(provider);
-
-        if (data == NULL) {
-            CGImageRelease(image);
-            return XCAP_ERR_CAPTURE_FAILED;
-        }
-
-        const uint8_t *src = CFDataGetBytePtr(data);
-        size_t data_length = CFDataGetLength(data);
-
-        // Allocate and copy data
-        result->data = (uint8_t *)malloc(data_length);
-        if (result->data == NULL) {
-            CFRelease(data);
-            CGImageRelease(image);
-            return XCAP_ERR_ALLOC_FAILED;
-        }
-
-        memcpy(result->data, src, data_length);
-        result->width = (uint32_t)width;
-        result->height = (uint32_t)height;
-        result->bytes_per_row = (uint32_t)bytes_per_row;
-        result->data_length = (uint32_t)data_length;
-
-        CFRelease(data);
+        // Copy pixel data as sRGB BGRA
+        int ret = copy_image_to_result(image, result);
         CGImageRelease(image);
 
-        return XCAP_OK;
+        return ret;
     }
 }
 
@@ -471,3 +891,98 @@ uint32_t xcap_get_frontmost_window_id(void) {
 uint32_t xcap_get_current_pid(void) {
     return (uint32_t)getpid();
 }
+
+#pragma mark - Cursor Functions
+
+int xcap_get_cursor_position(int32_t *x, int32_t *y) {
+    // Event locations use the same top-left origin global coordinates as CGDisplayBounds
+    CGEventRef event = CGEventCreate(NULL);
+    if (event == NULL) {
+        return XCAP_ERR_CAPTURE_FAILED;
+    }
+
+    CGPoint location = CGEventGetLocation(event);
+    CFRelease(event);
+
+    *x = (int32_t)floor(location.x);
+    *y = (int32_t)floor(location.y);
+    return XCAP_OK;
+}
+
+// Cursor image read on the main thread
+typedef struct {
+    MainCall call;
+    int ret;
+    XcapCursorInfo info;
+} CursorCall;
+
+static void cursor_call_run(MainCall *call) {
+    @autoreleasepool {
+        CursorCall *cc = (CursorCall *)call;
+        XcapCursorInfo *info = &cc->info;
+
+        // currentSystemCursor returns the cursor shown system-wide, not just in this app
+        NSCursor *cursor = [NSCursor currentSystemCursor];
+        if (cursor == nil) {
+            cc->ret = XCAP_OK;
+            return;
+        }
+
+        NSImage *image = [cursor image];
+        NSSize size = [image size];
+        CGImageRef cg_image = [image CGImageForProposedRect:NULL context:nil hints:nil];
+        if (cg_image == NULL || size.width <= 0) {
+            cc->ret = XCAP_ERR_CAPTURE_FAILED;
+            return;
+        }
+
+        // cg_image is owned by the NSImage, do not release it
+        cc->ret = copy_image_to_result(cg_image, &info->image);
+        if (cc->ret != XCAP_OK) {
+            return;
+        }
+
+        NSPoint hotspot = [cursor hotSpot];
+        info->scale = (float)(CGImageGetWidth(cg_image) / size.width);
+        info->hotspot_x = (int32_t)lround(hotspot.x * info->scale);
+        info->hotspot_y = (int32_t)lround(hotspot.y * info->scale);
+        info->visible = true;
+    }
+}
+
+static void cursor_call_cleanup(MainCall *call) {
+    xcap_free_capture_result(&((CursorCall *)call)->info.image);
+}
+
+int xcap_capture_cursor(XcapCursorInfo *info) {
+    memset(info, 0, sizeof(*info));
+
+    CursorCall *cc = (CursorCall *)calloc(1, sizeof(CursorCall));
+    if (cc == NULL) {
+        return XCAP_ERR_ALLOC_FAILED;
+    }
+    cc->call.run = cursor_call_run;
+    cc->call.cleanup = cursor_call_cleanup;
+
+    if (!run_on_main_thread(&cc->call)) {
+        main_call_release(&cc->call);
+        return XCAP_ERR_MAIN_THREAD_UNAVAILABLE;
+    }
+
+    // Take the image so cleanup does not free it
+    int ret = cc->ret;
+    *info = cc->info;
+    memset(&cc->info, 0, sizeof(cc->info));
+    main_call_release(&cc->call);
+    return ret;
+}
+
+#pragma mark - Permission Functions
+
+bool xcap_check_screen_capture_access(void) {
+    return CGPreflightScreenCaptureAccess();
+}
+
+bool xcap_request_screen_capture_access(void) {
+    return CGRequestScreenCaptureAccess();
+}
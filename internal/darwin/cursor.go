@@ -0,0 +1,47 @@
+//go:build darwin
+
+package darwin
+
+import "image"
+
+// Cursor 表示 macOS 上的鼠标指针图像
+type Cursor struct {
+	// Image 指针图像（像素），Visible 为 false 时为 nil
+	Image *image.RGBA
+
+	// Hotspot 热点在 Image 中的像素坐标
+	Hotspot image.Point
+
+	// Scale Image 中每个点对应的像素数，Retina 光标通常为 2
+	Scale float32
+
+	// Visible 是否能获取到指针
+	Visible bool
+}
+
+// CursorPosition 返回鼠标指针的全局坐标（点），与 Monitor.X()/Y() 同一坐标系
+func CursorPosition() (image.Point, error) {
+	x, y, err := GetCursorPosition()
+	if err != nil {
+		return image.Point{}, err
+	}
+	return image.Pt(int(x), int(y)), nil
+}
+
+// CursorImage 返回当前系统鼠标指针的图像
+func CursorImage() (*Cursor, error) {
+	result, err := CaptureCursor()
+	if err != nil {
+		return nil, err
+	}
+	if !result.Visible {
+		return &Cursor{}, nil
+	}
+
+	return &Cursor{
+		Image:   CaptureResultToImage(result.Image),
+		Hotspot: image.Pt(int(result.HotspotX), int(result.HotspotY)),
+		Scale:   result.Scale,
+		Visible: true,
+	}, nil
+}
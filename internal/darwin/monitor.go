@@ -3,12 +3,16 @@
 package darwin
 
 import (
-	"errors"
 	"image"
+
+	"github.com/zn-chen/xcap/internal/errcode"
 )
 
 // ErrNotSupported 在功能未实现时返回
-var ErrNotSupported = errors.New("not supported")
+var ErrNotSupported = errcode.ErrNotSupported
+
+// ErrInvalidRegion 在截图区域超出显示器范围时返回
+var ErrInvalidRegion = errcode.ErrInvalidRegion
 
 // Monitor 表示 macOS 上的显示器
 type Monitor struct {
@@ -65,9 +69,9 @@ func (m *Monitor) Height() uint32 {
 	return m.info.Height
 }
 
-// Rotation 返回旋转角度（最小版本暂未实现）
+// Rotation 返回画面相对帧缓冲的顺时针旋转角度（0、90、180、270）
 func (m *Monitor) Rotation() float32 {
-	return 0 // TODO: 在完整版本中实现
+	return m.info.Rotation
 }
 
 // ScaleFactor 返回 DPI 缩放因子
@@ -75,9 +79,9 @@ func (m *Monitor) ScaleFactor() float32 {
 	return m.info.ScaleFactor
 }
 
-// Frequency 返回刷新率（暂未实现）
+// Frequency 返回刷新率（Hz），未知时为 0
 func (m *Monitor) Frequency() float32 {
-	return 0 // TODO: 在完整版本中实现
+	return m.info.Frequency
 }
 
 // IsPrimary 返回是否为主显示器
@@ -85,13 +89,22 @@ func (m *Monitor) IsPrimary() bool {
 	return m.info.IsPrimary
 }
 
-// IsBuiltin 返回是否为内置显示器（最小版本暂未实现）
+// IsBuiltin 返回是否为内置显示器
 func (m *Monitor) IsBuiltin() bool {
-	return false // TODO: 在完整版本中实现
+	return m.info.IsBuiltin
+}
+
+// Details 返回显示器的硬件信息（厂商、型号、序列号、物理尺寸和 EDID）
+func (m *Monitor) Details() (*MonitorDetails, error) {
+	return GetMonitorDetails(m.info.ID)
 }
 
 // CaptureImage 截取整个显示器，返回 RGBA 图像
 func (m *Monitor) CaptureImage() (*image.RGBA, error) {
+	if err := checkCaptureAccess("capture monitor"); err != nil {
+		return nil, err
+	}
+
 	result, err := CaptureMonitor(m.info.ID)
 	if err != nil {
 		return nil, err
@@ -100,7 +113,46 @@ func (m *Monitor) CaptureImage() (*image.RGBA, error) {
 	return CaptureResultToImage(result), nil
 }
 
-// CaptureRegion 截取显示器的指定区域（最小版本暂未实现）
+// CaptureRegion 截取显示器的指定区域
+// 区域相对显示器左上角，单位为点；Retina 显示器上返回的图像为物理像素尺寸
 func (m *Monitor) CaptureRegion(x, y, width, height uint32) (*image.RGBA, error) {
-	return nil, ErrNotSupported // TODO: 在完整版本中实现
+	if width == 0 || height == 0 || uint64(x)+uint64(width) > uint64(m.info.Width) ||
+		uint64(y)+uint64(height) > uint64(m.info.Height) {
+		return nil, ErrInvalidRegion
+	}
+	if err := checkCaptureAccess("capture monitor region"); err != nil {
+		return nil, err
+	}
+
+	result, err := CaptureMonitorRegion(m.info.ID, int32(x), int32(y), width, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return CaptureResultToImage(result), nil
+}
+
+// CaptureExcluding 截取整个显示器，但不包含 windowIDs 中的窗口
+// 由窗口服务器直接合成其余窗口，被排除窗口后面的内容（包括桌面背景）会正常显示
+func (m *Monitor) CaptureExcluding(windowIDs []uint32) (*image.RGBA, error) {
+	if err := checkCaptureAccess("capture monitor excluding windows"); err != nil {
+		return nil, err
+	}
+
+	result, err := CaptureMonitorExcluding(m.info.ID, windowIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return CaptureResultToImage(result), nil
+}
+
+// checkCaptureAccess 在缺少屏幕录制权限时返回 PermissionDenied 错误
+// 没有权限时 CGWindowListCreateImage 并不会失败，而是只返回桌面背景和菜单栏，
+// 因此需要在截图前检查
+func checkCaptureAccess(op string) error {
+	if CheckScreenCaptureAccess() {
+		return nil
+	}
+	return &errcode.Error{Op: op, Code: errcode.PermissionDenied}
 }
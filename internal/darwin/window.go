@@ -2,13 +2,12 @@
 
 package darwin
 
-import (
-	"image"
-)
+import "image"
 
 // Window 表示 macOS 上的应用程序窗口
 type Window struct {
 	info WindowInfo
+	z    int
 }
 
 // NewWindow 从 WindowInfo 创建新的 Window
@@ -29,14 +28,26 @@ func AllWindowsWithOptions(excludeCurrentProcess bool) ([]*Window, error) {
 		return nil, err
 	}
 
+	// 窗口列表按从前到后的顺序返回
 	windows := make([]*Window, len(infos))
 	for i, info := range infos {
 		windows[i] = NewWindow(info)
+		windows[i].z = len(infos) - i
 	}
 
 	return windows, nil
 }
 
+// WindowByID 直接查询 ID 为 id 的窗口，不经过枚举的过滤，窗口不存在时返回 NotFound 错误
+// 单个窗口的查询没有 Z 顺序，Z 为 0
+func WindowByID(id uint32) (*Window, error) {
+	info, err := GetWindowInfo(id)
+	if err != nil {
+		return nil, err
+	}
+	return NewWindow(info), nil
+}
+
 // ID 返回窗口的唯一标识符
 func (w *Window) ID() uint32 {
 	return w.info.ID
@@ -67,9 +78,10 @@ func (w *Window) Y() int {
 	return int(w.info.Y)
 }
 
-// Z 返回窗口的 Z 顺序（最小版本暂未实现）
+// Z 返回窗口的 Z 顺序，值越大越靠前，最前面的窗口等于枚举到的窗口总数
+// 只在同一次枚举得到的窗口之间可比较
 func (w *Window) Z() int {
-	return 0 // TODO: 在完整版本中实现
+	return w.z
 }
 
 // Width 返回窗口的宽度（像素）
@@ -82,6 +94,16 @@ func (w *Window) Height() uint32 {
 	return w.info.Height
 }
 
+// Refresh 重新读取窗口的标题、位置和尺寸，返回新的 Window，Z 保持枚举时的值
+// 窗口已关闭时返回 NotFound 错误；ID 是否被其他进程的窗口复用由调用方比较 PID 判断
+func (w *Window) Refresh() (*Window, error) {
+	info, err := GetWindowInfo(w.info.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &Window{info: info, z: w.z}, nil
+}
+
 // IsMinimized 返回窗口是否最小化
 // macOS 上需要 Accessibility 权限，当前未实现
 func (w *Window) IsMinimized() (bool, error) {
@@ -100,14 +122,91 @@ func (w *Window) IsFocused() (bool, error) {
 	return w.info.ID == frontID, nil
 }
 
+// Bounds 返回窗口框架的当前位置（点）
+// 窗口服务器不提供标题栏高度和阴影范围，因此只有框架区域
+func (w *Window) Bounds() (image.Rectangle, error) {
+	info, err := GetWindowInfo(w.info.ID)
+	if err != nil {
+		return image.Rectangle{}, err
+	}
+	return image.Rect(int(info.X), int(info.Y), int(info.X)+int(info.Width), int(info.Y)+int(info.Height)), nil
+}
+
+// Class 返回窗口类名，macOS 没有窗口类的概念，返回 ErrNotSupported
+func (w *Window) Class() (string, error) {
+	return "", ErrNotSupported
+}
+
+// Type 返回窗口类型（WindowType* 常量），根据窗口层级推断
+// 与主窗口同层级的面板和工作表（sheet）被视为普通窗口
+func (w *Window) Type() (int, error) {
+	d, err := GetWindowDetails(w.info.ID)
+	return d.Type, err
+}
+
+// ProcessPath 返回所属进程的可执行文件路径，无法读取时返回 ErrNotSupported
+func (w *Window) ProcessPath() (string, error) {
+	d, err := GetWindowDetails(w.info.ID)
+	if err != nil {
+		return "", err
+	}
+	if d.ProcessPath == "" {
+		return "", ErrNotSupported
+	}
+	return d.ProcessPath, nil
+}
+
+// OwnerID 返回所有者窗口的 ID
+// CGWindow 不提供窗口之间的从属关系，返回 ErrNotSupported
+func (w *Window) OwnerID() (uint32, error) {
+	return 0, ErrNotSupported
+}
+
+// ParentID 返回父窗口的 ID
+// CGWindow 不提供窗口之间的从属关系，返回 ErrNotSupported
+func (w *Window) ParentID() (uint32, error) {
+	return 0, ErrNotSupported
+}
+
+// IsModal 返回窗口是否为模态窗口，即位于模态面板层级
+// 附着在窗口上的工作表（sheet）无法识别，返回 false
+func (w *Window) IsModal() (bool, error) {
+	d, err := GetWindowDetails(w.info.ID)
+	return d.Modal, err
+}
+
+// Opacity 返回窗口的不透明度（0 到 1）
+func (w *Window) Opacity() (float64, error) {
+	d, err := GetWindowDetails(w.info.ID)
+	return float64(d.Opacity), err
+}
+
 // CurrentMonitor 返回窗口所在的显示器（最小版本暂未实现）
 func (w *Window) CurrentMonitor() (*Monitor, error) {
 	return nil, ErrNotSupported // TODO: 在完整版本中实现
 }
 
 // CaptureImage 截取窗口内容，返回 RGBA 图像
+// 截取其他进程的窗口需要屏幕录制权限
 func (w *Window) CaptureImage() (*image.RGBA, error) {
-	result, err := CaptureWindow(w.info.ID)
+	return w.capture(CaptureWindow)
+}
+
+// CaptureImageWithShadow 截取窗口内容和窗口服务器绘制的阴影
+// 图像不裁剪到窗口框架，因此比 Width/Height 对应的像素尺寸大
+func (w *Window) CaptureImageWithShadow() (*image.RGBA, error) {
+	return w.capture(CaptureWindowWithShadow)
+}
+
+// capture 检查权限后用 captureFn 截取窗口
+func (w *Window) capture(captureFn func(uint32) (*CaptureResult, error)) (*image.RGBA, error) {
+	if w.info.PID != GetCurrentPID() {
+		if err := checkCaptureAccess("capture window"); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := captureFn(w.info.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,68 @@
+// Package errcode 定义各平台 C 桥接层共用的错误码和错误类型
+//
+// 不依赖 cgo，pkg/xcap 据此将后端错误映射为公开的错误。
+package errcode
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Code 是 C 桥接层返回的错误码，与各平台 bridge.h 中的 XCAP_* 定义一致
+type Code int
+
+// 错误码
+const (
+	OK            Code = 0
+	NoMonitors    Code = 1
+	NoWindows     Code = 2
+	CaptureFailed Code = 3
+	AllocFailed   Code = 4
+	NotFound      Code = 5
+
+	// PermissionDenied 表示缺少截图权限（如 macOS 未授予屏幕录制权限）
+	PermissionDenied Code = 6
+)
+
+// String 返回错误码的名称
+func (c Code) String() string {
+	switch c {
+	case OK:
+		return "ok"
+	case NoMonitors:
+		return "no monitors"
+	case NoWindows:
+		return "no windows"
+	case CaptureFailed:
+		return "capture failed"
+	case AllocFailed:
+		return "allocation failed"
+	case NotFound:
+		return "not found"
+	case PermissionDenied:
+		return "permission denied"
+	}
+	return fmt.Sprintf("unknown error %d", int(c))
+}
+
+// Go 层的错误，不来自 C 桥接层
+var (
+	// ErrNotSupported 在功能未实现时返回
+	ErrNotSupported = errors.New("not supported")
+
+	// ErrInvalidRegion 在截图区域超出显示器范围时返回
+	ErrInvalidRegion = errors.New("invalid capture region")
+)
+
+// Error 表示 C 桥接层调用失败
+type Error struct {
+	// Op 失败的操作，如 "capture monitor"
+	Op string
+
+	// Code C 层返回的错误码
+	Code Code
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("failed to %s: error code %d (%s)", e.Op, int(e.Code), e.Code)
+}
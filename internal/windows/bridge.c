@@ -2,8 +2,10 @@
 #include <dwmapi.h>
 #include <shellscalingapi.h>
 #include <psapi.h>
+#include <setupapi.h>
 #include <stdlib.h>
 #include <string.h>
+#include <wchar.h>
 #include "bridge.h"
 
 // Link required libraries
@@ -11,6 +13,8 @@
 #pragma comment(lib, "gdi32.lib")
 #pragma comment(lib, "dwmapi.lib")
 #pragma comment(lib, "shcore.lib")
+#pragma comment(lib, "setupapi.lib")
+#pragma comment(lib, "advapi32.lib")
 
 // Constants (only define if not already defined by Windows headers)
 #ifndef DWMWA_CLOAKED
@@ -23,6 +27,16 @@
 #define PW_RENDERFULLCONTENT 2
 #endif
 
+// Output technologies added in Windows 10, missing from older SDK headers
+#define XCAP_OUTPUT_TECHNOLOGY_MIRACAST 15
+#define XCAP_OUTPUT_TECHNOLOGY_INDIRECT_WIRED 16
+#define XCAP_OUTPUT_TECHNOLOGY_INDIRECT_VIRTUAL 17
+
+// Monitor device interface class {E6F07B5F-EE97-4A90-B076-33F57BF4EAA7}
+static const GUID monitor_interface_guid = {
+    0xe6f07b5f, 0xee97, 0x4a90, {0xb0, 0x76, 0x33, 0xf5, 0x7b, 0xf4, 0xea, 0xa7}
+};
+
 // DPI Awareness initialization (called once on library load)
 static void init_dpi_awareness(void) {
     // Try Windows 10 1703+ API first
@@ -61,6 +75,118 @@ typedef struct {
     int capacity;
 } EnumMonitorData;
 
+// Current mode of a display device: orientation in clockwise degrees,
+// integer refresh rate and color depth
+static void fill_display_settings(const WCHAR *device, XcapMonitorInfo *info) {
+    DEVMODEW dm;
+    memset(&dm, 0, sizeof(dm));
+    dm.dmSize = sizeof(dm);
+    if (!EnumDisplaySettingsW(device, ENUM_CURRENT_SETTINGS, &dm)) {
+        return;
+    }
+
+    if (dm.dmFields & DM_DISPLAYORIENTATION) {
+        switch (dm.dmDisplayOrientation) {
+        case DMDO_90:
+            info->rotation = 90;
+            break;
+        case DMDO_180:
+            info->rotation = 180;
+            break;
+        case DMDO_270:
+            info->rotation = 270;
+            break;
+        }
+    }
+
+    // 0 and 1 both mean the hardware default refresh rate
+    if ((dm.dmFields & DM_DISPLAYFREQUENCY) && dm.dmDisplayFrequency > 1) {
+        info->frequency = (float)dm.dmDisplayFrequency;
+    }
+    if (dm.dmFields & DM_BITSPERPEL) {
+        info->bits_per_pixel = dm.dmBitsPerPel;
+    }
+}
+
+static int32_t connector_from_output_technology(DISPLAYCONFIG_VIDEO_OUTPUT_TECHNOLOGY tech) {
+    switch ((uint32_t)tech) {
+    case DISPLAYCONFIG_OUTPUT_TECHNOLOGY_HD15:
+        return XCAP_CONNECTOR_VGA;
+    case DISPLAYCONFIG_OUTPUT_TECHNOLOGY_DVI:
+        return XCAP_CONNECTOR_DVI;
+    case DISPLAYCONFIG_OUTPUT_TECHNOLOGY_HDMI:
+        return XCAP_CONNECTOR_HDMI;
+    case DISPLAYCONFIG_OUTPUT_TECHNOLOGY_DISPLAYPORT_EXTERNAL:
+        return XCAP_CONNECTOR_DISPLAYPORT;
+    case DISPLAYCONFIG_OUTPUT_TECHNOLOGY_LVDS:
+    case DISPLAYCONFIG_OUTPUT_TECHNOLOGY_DISPLAYPORT_EMBEDDED:
+    case DISPLAYCONFIG_OUTPUT_TECHNOLOGY_UDI_EMBEDDED:
+    case (uint32_t)DISPLAYCONFIG_OUTPUT_TECHNOLOGY_INTERNAL:
+        return XCAP_CONNECTOR_INTERNAL;
+    case XCAP_OUTPUT_TECHNOLOGY_MIRACAST:
+        return XCAP_CONNECTOR_WIRELESS;
+    case XCAP_OUTPUT_TECHNOLOGY_INDIRECT_WIRED:
+    case XCAP_OUTPUT_TECHNOLOGY_INDIRECT_VIRTUAL:
+        return XCAP_CONNECTOR_VIRTUAL;
+    default:
+        return XCAP_CONNECTOR_UNKNOWN;
+    }
+}
+
+// Connector, exact refresh rate and friendly name from the active display path
+// that drives a GDI device (Windows 7+)
+static void fill_display_path(const WCHAR *device, XcapMonitorInfo *info) {
+    UINT32 path_count = 0;
+    UINT32 mode_count = 0;
+    if (GetDisplayConfigBufferSizes(QDC_ONLY_ACTIVE_PATHS, &path_count, &mode_count) != ERROR_SUCCESS) {
+        return;
+    }
+
+    DISPLAYCONFIG_PATH_INFO *paths = (DISPLAYCONFIG_PATH_INFO *)calloc(path_count, sizeof(*paths));
+    DISPLAYCONFIG_MODE_INFO *modes = (DISPLAYCONFIG_MODE_INFO *)calloc(mode_count, sizeof(*modes));
+    if (paths == NULL || modes == NULL ||
+        QueryDisplayConfig(QDC_ONLY_ACTIVE_PATHS, &path_count, paths, &mode_count, modes, NULL) != ERROR_SUCCESS) {
+        free(paths);
+        free(modes);
+        return;
+    }
+
+    for (UINT32 i = 0; i < path_count; i++) {
+        DISPLAYCONFIG_SOURCE_DEVICE_NAME source;
+        memset(&source, 0, sizeof(source));
+        source.header.type = DISPLAYCONFIG_DEVICE_INFO_GET_SOURCE_NAME;
+        source.header.size = sizeof(source);
+        source.header.adapterId = paths[i].sourceInfo.adapterId;
+        source.header.id = paths[i].sourceInfo.id;
+        if (DisplayConfigGetDeviceInfo(&source.header) != ERROR_SUCCESS ||
+            wcscmp(source.viewGdiDeviceName, device) != 0) {
+            continue;
+        }
+
+        info->connector = connector_from_output_technology(paths[i].targetInfo.outputTechnology);
+
+        DISPLAYCONFIG_RATIONAL rate = paths[i].targetInfo.refreshRate;
+        if (rate.Numerator != 0 && rate.Denominator != 0) {
+            info->frequency = (float)((double)rate.Numerator / rate.Denominator);
+        }
+
+        DISPLAYCONFIG_TARGET_DEVICE_NAME target;
+        memset(&target, 0, sizeof(target));
+        target.header.type = DISPLAYCONFIG_DEVICE_INFO_GET_TARGET_NAME;
+        target.header.size = sizeof(target);
+        target.header.adapterId = paths[i].targetInfo.adapterId;
+        target.header.id = paths[i].targetInfo.id;
+        if (DisplayConfigGetDeviceInfo(&target.header) == ERROR_SUCCESS) {
+            memcpy(info->model, target.monitorFriendlyDeviceName, sizeof(info->model));
+            info->model[63] = 0;
+        }
+        break;
+    }
+
+    free(paths);
+    free(modes);
+}
+
 static BOOL CALLBACK monitor_enum_callback(HMONITOR hMonitor, HDC hdcMonitor,
                                            LPRECT lprcMonitor, LPARAM dwData) {
     EnumMonitorData *data = (EnumMonitorData *)dwData;
@@ -82,6 +208,7 @@ static BOOL CALLBACK monitor_enum_callback(HMONITOR hMonitor, HDC hdcMonitor,
 
     if (GetMonitorInfoW(hMonitor, (LPMONITORINFO)&mi)) {
         XcapMonitorInfo *info = &data->monitors[data->count];
+        memset(info, 0, sizeof(*info));
         info->handle = (uintptr_t)hMonitor;
         memcpy(info->name, mi.szDevice, sizeof(info->name));
         info->x = mi.rcMonitor.left;
@@ -89,6 +216,8 @@ static BOOL CALLBACK monitor_enum_callback(HMONITOR hMonitor, HDC hdcMonitor,
         info->width = (uint32_t)(mi.rcMonitor.right - mi.rcMonitor.left);
         info->height = (uint32_t)(mi.rcMonitor.bottom - mi.rcMonitor.top);
         info->is_primary = (mi.dwFlags & MONITORINFOF_PRIMARY) != 0;
+        fill_display_settings(mi.szDevice, info);
+        fill_display_path(mi.szDevice, info);
         data->count++;
     }
 
@@ -137,6 +266,72 @@ int xcap_get_monitor_dpi(uintptr_t handle, uint32_t *dpi_x, uint32_t *dpi_y) {
     return XCAP_OK;
 }
 
+// Raw EDID of the monitor attached to an HMONITOR, read from the device's
+// registry key (Device Parameters\EDID) via SetupAPI
+int xcap_get_monitor_edid(uintptr_t handle, uint8_t **data, uint32_t *length) {
+    *data = NULL;
+    *length = 0;
+
+    MONITORINFOEXW mi;
+    mi.cbSize = sizeof(mi);
+    if (!GetMonitorInfoW((HMONITOR)handle, (LPMONITORINFO)&mi)) {
+        return XCAP_ERR_NOT_FOUND;
+    }
+
+    // First monitor attached to the adapter output, as a device interface path
+    DISPLAY_DEVICEW dd;
+    memset(&dd, 0, sizeof(dd));
+    dd.cb = sizeof(dd);
+    if (!EnumDisplayDevicesW(mi.szDevice, 0, &dd, EDD_GET_DEVICE_INTERFACE_NAME)) {
+        return XCAP_ERR_NOT_FOUND;
+    }
+
+    HDEVINFO devs = SetupDiGetClassDevsW(&monitor_interface_guid, NULL, NULL,
+                                         DIGCF_DEVICEINTERFACE | DIGCF_PRESENT);
+    if (devs == INVALID_HANDLE_VALUE) {
+        return XCAP_ERR_NOT_FOUND;
+    }
+
+    int rc = XCAP_ERR_NOT_FOUND;
+    SP_DEVICE_INTERFACE_DATA iface;
+    memset(&iface, 0, sizeof(iface));
+    iface.cbSize = sizeof(iface);
+    SP_DEVINFO_DATA dev;
+    memset(&dev, 0, sizeof(dev));
+    dev.cbSize = sizeof(dev);
+
+    if (SetupDiOpenDeviceInterfaceW(devs, dd.DeviceID, 0, &iface)) {
+        // Only the device info is needed; this fails with ERROR_INSUFFICIENT_BUFFER
+        // but still fills dev
+        SetupDiGetDeviceInterfaceDetailW(devs, &iface, NULL, 0, NULL, &dev);
+
+        HKEY key = SetupDiOpenDevRegKey(devs, &dev, DICS_FLAG_GLOBAL, 0, DIREG_DEV, KEY_READ);
+        if (key != INVALID_HANDLE_VALUE) {
+            DWORD size = 0;
+            if (RegQueryValueExW(key, L"EDID", NULL, NULL, NULL, &size) == ERROR_SUCCESS && size > 0) {
+                uint8_t *buf = (uint8_t *)malloc(size);
+                if (buf == NULL) {
+                    rc = XCAP_ERR_ALLOC_FAILED;
+                } else if (RegQueryValueExW(key, L"EDID", NULL, NULL, buf, &size) == ERROR_SUCCESS) {
+                    *data = buf;
+                    *length = (uint32_t)size;
+                    rc = XCAP_OK;
+                } else {
+                    free(buf);
+                }
+            }
+            RegCloseKey(key);
+        }
+    }
+
+    SetupDiDestroyDeviceInfoList(devs);
+    return rc;
+}
+
+void xcap_free_edid(uint8_t *data) {
+    free(data);
+}
+
 int xcap_capture_monitor(uintptr_t handle, int32_t x, int32_t y,
                          uint32_t width, uint32_t height, XcapCaptureResult *result) {
     if (width == 0 || height == 0) {
@@ -172,11 +367,14 @@ int xcap_capture_monitor(uintptr_t handle, int32_t x, int32_t y,
                               hdc_screen, x, y, SRCCOPY);
 
     if (!blt_result) {
+        // BitBlt fails with ERROR_ACCESS_DENIED while the secure desktop
+        // (UAC prompt, lock screen) is active
+        DWORD blt_error = GetLastError();
         SelectObject(hdc_mem, old_bitmap);
         DeleteObject(hbitmap);
         DeleteDC(hdc_mem);
         ReleaseDC(hwnd_desktop, hdc_screen);
-        return XCAP_ERR_CAPTURE_FAILED;
+        return blt_error == ERROR_ACCESS_DENIED ? XCAP_ERR_PERMISSION_DENIED : XCAP_ERR_CAPTURE_FAILED;
     }
 
     // Prepare bitmap info
@@ -257,6 +455,26 @@ static void get_process_name(DWORD pid, WCHAR *name_buf, int buf_size) {
     }
 }
 
+static void fill_window_info(HWND hwnd, DWORD pid, const RECT *rect, XcapWindowInfo *info) {
+    memset(info, 0, sizeof(XcapWindowInfo));
+
+    info->handle = (uintptr_t)hwnd;
+    info->pid = pid;
+    info->x = rect->left;
+    info->y = rect->top;
+    info->width = (uint32_t)(rect->right - rect->left);
+    info->height = (uint32_t)(rect->bottom - rect->top);
+
+    // Get window title
+    int title_len = GetWindowTextLengthW(hwnd);
+    if (title_len > 0 && title_len < 255) {
+        GetWindowTextW(hwnd, (LPWSTR)info->title, 256);
+    }
+
+    // Get process name
+    get_process_name(pid, (LPWSTR)info->app_name, 260);
+}
+
 static BOOL CALLBACK window_enum_callback(HWND hwnd, LPARAM lParam) {
     EnumWindowData *data = (EnumWindowData *)lParam;
 
@@ -330,25 +548,7 @@ static BOOL CALLBACK window_enum_callback(HWND hwnd, LPARAM lParam) {
         data->capacity = new_capacity;
     }
 
-    XcapWindowInfo *info = &data->windows[data->count];
-    memset(info, 0, sizeof(XcapWindowInfo));
-
-    info->handle = (uintptr_t)hwnd;
-    info->pid = pid;
-    info->x = rect.left;
-    info->y = rect.top;
-    info->width = (uint32_t)width;
-    info->height = (uint32_t)height;
-
-    // Get window title
-    int title_len = GetWindowTextLengthW(hwnd);
-    if (title_len > 0 && title_len < 255) {
-        GetWindowTextW(hwnd, (LPWSTR)info->title, 256);
-    }
-
-    // Get process name
-    get_process_name(pid, (LPWSTR)info->app_name, 260);
-
+    fill_window_info(hwnd, pid, &rect, &data->windows[data->count]);
     data->count++;
     return TRUE;
 }
@@ -387,6 +587,157 @@ void xcap_free_windows(XcapWindowInfo *windows) {
     }
 }
 
+// Live metadata of a single window; the enumeration filters are not applied
+int xcap_get_window_info(uintptr_t handle, XcapWindowInfo *info) {
+    HWND hwnd = (HWND)handle;
+    if (!IsWindow(hwnd)) {
+        return XCAP_ERR_NOT_FOUND;
+    }
+
+    DWORD pid = 0;
+    GetWindowThreadProcessId(hwnd, &pid);
+
+    RECT rect;
+    if (!GetWindowRect(hwnd, &rect)) {
+        return XCAP_ERR_NOT_FOUND;
+    }
+    get_extended_frame_bounds(hwnd, &rect);
+
+    fill_window_info(hwnd, pid, &rect, info);
+    return XCAP_OK;
+}
+
+// Classify a window from its class and styles
+// Standard dialogs and owned windows with a caption are treated as dialogs.
+// The enumeration drops WS_EX_TOOLWINDOW windows, so tooltips, menus and
+// utility windows are only classified for handles looked up directly
+static int32_t classify_window(HWND hwnd, const WCHAR *class_name, HWND owner) {
+    LONG_PTR style = GetWindowLongPtrW(hwnd, GWL_STYLE);
+    LONG_PTR ex_style = GetWindowLongPtrW(hwnd, GWL_EXSTYLE);
+
+    if (wcscmp(class_name, L"Shell_TrayWnd") == 0 ||
+        wcscmp(class_name, L"Shell_SecondaryTrayWnd") == 0) {
+        return XCAP_WINDOW_TYPE_DOCK;
+    }
+    if (wcscmp(class_name, L"Progman") == 0 || wcscmp(class_name, L"WorkerW") == 0) {
+        return XCAP_WINDOW_TYPE_DESKTOP;
+    }
+    if (wcscmp(class_name, L"tooltips_class32") == 0) {
+        return XCAP_WINDOW_TYPE_TOOLTIP;
+    }
+    if (wcscmp(class_name, L"#32768") == 0) {
+        return XCAP_WINDOW_TYPE_MENU;
+    }
+    if (wcscmp(class_name, L"#32770") == 0 || (ex_style & WS_EX_DLGMODALFRAME)) {
+        return XCAP_WINDOW_TYPE_DIALOG;
+    }
+    if (ex_style & WS_EX_TOOLWINDOW) {
+        return XCAP_WINDOW_TYPE_UTILITY;
+    }
+    if (owner != NULL && (style & WS_CAPTION) == WS_CAPTION) {
+        return XCAP_WINDOW_TYPE_DIALOG;
+    }
+    return XCAP_WINDOW_TYPE_NORMAL;
+}
+
+// Layered windows may set a constant alpha; all other windows are opaque
+static float get_window_opacity(HWND hwnd) {
+    if (!(GetWindowLongPtrW(hwnd, GWL_EXSTYLE) & WS_EX_LAYERED)) {
+        return 1.0f;
+    }
+
+    BYTE alpha = 255;
+    DWORD flags = 0;
+    if (GetLayeredWindowAttributes(hwnd, NULL, &alpha, &flags) && (flags & LWA_ALPHA)) {
+        return alpha / 255.0f;
+    }
+    return 1.0f;
+}
+
+// Limited query access is enough for the image name and is granted for
+// most processes, including elevated ones
+static void get_process_path(DWORD pid, WCHAR *path_buf, DWORD buf_size) {
+    path_buf[0] = L'\0';
+    HANDLE hProcess = OpenProcess(PROCESS_QUERY_LIMITED_INFORMATION, FALSE, pid);
+    if (hProcess != NULL) {
+        if (!QueryFullProcessImageNameW(hProcess, 0, path_buf, &buf_size)) {
+            path_buf[0] = L'\0';
+        }
+        CloseHandle(hProcess);
+    }
+}
+
+int xcap_get_window_details(uintptr_t handle, XcapWindowDetails *details) {
+    HWND hwnd = (HWND)handle;
+    memset(details, 0, sizeof(XcapWindowDetails));
+
+    if (!IsWindow(hwnd)) {
+        return XCAP_ERR_NOT_FOUND;
+    }
+
+    WCHAR *class_name = (WCHAR *)details->class_name;
+    GetClassNameW(hwnd, class_name, 256);
+
+    HWND owner = GetWindow(hwnd, GW_OWNER);
+    details->owner = (uintptr_t)owner;
+
+    // GA_PARENT returns the desktop window for top-level windows
+    HWND parent = GetAncestor(hwnd, GA_PARENT);
+    if (parent != NULL && parent != GetDesktopWindow()) {
+        details->parent = (uintptr_t)parent;
+    }
+
+    details->window_type = classify_window(hwnd, class_name, owner);
+
+    // A modal window disables its owner until it is closed
+    details->is_modal = owner != NULL && !IsWindowEnabled(owner);
+
+    details->opacity = get_window_opacity(hwnd);
+
+    DWORD pid = 0;
+    GetWindowThreadProcessId(hwnd, &pid);
+    get_process_path(pid, (LPWSTR)details->process_path, 1024);
+
+    return XCAP_OK;
+}
+
+static void rect_to_xcap(const RECT *rect, XcapRect *out) {
+    out->left = rect->left;
+    out->top = rect->top;
+    out->right = rect->right;
+    out->bottom = rect->bottom;
+}
+
+int xcap_get_window_bounds(uintptr_t handle, XcapWindowBounds *bounds) {
+    HWND hwnd = (HWND)handle;
+    memset(bounds, 0, sizeof(XcapWindowBounds));
+
+    if (!IsWindow(hwnd)) {
+        return XCAP_ERR_NOT_FOUND;
+    }
+
+    RECT rect;
+    if (!GetWindowRect(hwnd, &rect)) {
+        return XCAP_ERR_NOT_FOUND;
+    }
+    rect_to_xcap(&rect, &bounds->window);
+
+    // Without DWM the frame is the whole window rect
+    get_extended_frame_bounds(hwnd, &rect);
+    rect_to_xcap(&rect, &bounds->frame);
+
+    // The client rect is relative to the client area origin
+    RECT client;
+    if (GetClientRect(hwnd, &client)) {
+        POINT origin = {0, 0};
+        ClientToScreen(hwnd, &origin);
+        OffsetRect(&client, origin.x, origin.y);
+        rect_to_xcap(&client, &bounds->client);
+    }
+
+    return XCAP_OK;
+}
+
 bool xcap_is_window_minimized(uintptr_t handle) {
     return IsIconic((HWND)handle) != 0;
 }
@@ -402,6 +753,10 @@ bool xcap_is_window_focused(uintptr_t handle) {
 int xcap_capture_window(uintptr_t handle, XcapCaptureResult *result) {
     HWND hwnd = (HWND)handle;
 
+    if (!IsWindow(hwnd)) {
+        return XCAP_ERR_NOT_FOUND;
+    }
+
     // Get window rect
     RECT rect;
     if (!GetWindowRect(hwnd, &rect)) {
@@ -529,6 +884,150 @@ int xcap_capture_window(uintptr_t handle, XcapCaptureResult *result) {
     return XCAP_OK;
 }
 
+// ============================================================================
+// Cursor Functions
+// ============================================================================
+
+int xcap_get_cursor_position(int32_t *x, int32_t *y) {
+    // Physical pixels, since the process is per-monitor DPI aware
+    POINT pt;
+    if (!GetCursorPos(&pt)) {
+        return XCAP_ERR_CAPTURE_FAILED;
+    }
+
+    *x = (int32_t)pt.x;
+    *y = (int32_t)pt.y;
+    return XCAP_OK;
+}
+
+// Draw the cursor over a solid background into a 32-bit top-down buffer
+static uint8_t *render_cursor(HCURSOR cursor, int width, int height, COLORREF background) {
+    HDC hdc_screen = GetDC(NULL);
+    if (hdc_screen == NULL) {
+        return NULL;
+    }
+
+    HDC hdc_mem = CreateCompatibleDC(hdc_screen);
+    if (hdc_mem == NULL) {
+        ReleaseDC(NULL, hdc_screen);
+        return NULL;
+    }
+
+    BITMAPINFO bi;
+    memset(&bi, 0, sizeof(bi));
+    bi.bmiHeader.biSize = sizeof(BITMAPINFOHEADER);
+    bi.bmiHeader.biWidth = width;
+    bi.bmiHeader.biHeight = -height;  // Top-down DIB
+    bi.bmiHeader.biPlanes = 1;
+    bi.bmiHeader.biBitCount = 32;
+    bi.bmiHeader.biCompression = BI_RGB;
+
+    void *bits = NULL;
+    HBITMAP dib = CreateDIBSection(hdc_screen, &bi, DIB_RGB_COLORS, &bits, NULL, 0);
+    if (dib == NULL) {
+        DeleteDC(hdc_mem);
+        ReleaseDC(NULL, hdc_screen);
+        return NULL;
+    }
+
+    HGDIOBJ old_bitmap = SelectObject(hdc_mem, dib);
+
+    RECT rect = {0, 0, width, height};
+    HBRUSH brush = CreateSolidBrush(background);
+    FillRect(hdc_mem, &rect, brush);
+    DeleteObject(brush);
+
+    DrawIconEx(hdc_mem, 0, 0, cursor, width, height, 0, NULL, DI_NORMAL);
+    GdiFlush();
+
+    size_t data_size = (size_t)width * height * 4;
+    uint8_t *data = (uint8_t *)malloc(data_size);
+    if (data != NULL) {
+        memcpy(data, bits, data_size);
+    }
+
+    SelectObject(hdc_mem, old_bitmap);
+    DeleteObject(dib);
+    DeleteDC(hdc_mem);
+    ReleaseDC(NULL, hdc_screen);
+
+    return data;
+}
+
+int xcap_capture_cursor(XcapCursorInfo *info) {
+    memset(info, 0, sizeof(*info));
+
+    CURSORINFO ci;
+    memset(&ci, 0, sizeof(ci));
+    ci.cbSize = sizeof(ci);
+    if (!GetCursorInfo(&ci)) {
+        return XCAP_ERR_CAPTURE_FAILED;
+    }
+    if (!(ci.flags & CURSOR_SHOWING) || ci.hCursor == NULL) {
+        return XCAP_OK;
+    }
+
+    ICONINFO ii;
+    if (!GetIconInfo(ci.hCursor, &ii)) {
+        return XCAP_ERR_CAPTURE_FAILED;
+    }
+
+    // Monochrome cursors store the AND and XOR masks stacked in hbmMask
+    BITMAP bm;
+    int width = 0;
+    int height = 0;
+    if (ii.hbmColor && GetObject(ii.hbmColor, sizeof(bm), &bm)) {
+        width = bm.bmWidth;
+        height = bm.bmHeight;
+    } else if (ii.hbmMask && GetObject(ii.hbmMask, sizeof(bm), &bm)) {
+        width = bm.bmWidth;
+        height = bm.bmHeight / 2;
+    }
+    if (ii.hbmColor) DeleteObject(ii.hbmColor);
+    if (ii.hbmMask) DeleteObject(ii.hbmMask);
+
+    if (width <= 0 || height <= 0) {
+        return XCAP_ERR_CAPTURE_FAILED;
+    }
+
+    // DrawIconEx does not produce an alpha channel for every cursor type, so render
+    // over black and white and recover it: white - black = 255 * (1 - alpha)
+    uint8_t *on_black = render_cursor(ci.hCursor, width, height, RGB(0, 0, 0));
+    uint8_t *on_white = render_cursor(ci.hCursor, width, height, RGB(255, 255, 255));
+    if (on_black == NULL || on_white == NULL) {
+        free(on_black);
+        free(on_white);
+        return XCAP_ERR_ALLOC_FAILED;
+    }
+
+    uint32_t data_size = (uint32_t)width * height * 4;
+    for (uint32_t i = 0; i < data_size; i += 4) {
+        int diff = 0;
+        for (int c = 0; c < 3; c++) {
+            int d = (int)on_white[i + c] - (int)on_black[i + c];
+            if (d > diff) diff = d;
+        }
+        uint8_t alpha = (uint8_t)(255 - diff);
+
+        // Drawn over black, the color channels are already premultiplied
+        for (int c = 0; c < 3; c++) {
+            if (on_black[i + c] > alpha) on_black[i + c] = alpha;
+        }
+        on_black[i + 3] = alpha;
+    }
+    free(on_white);
+
+    info->image.data = on_black;
+    info->image.width = (uint32_t)width;
+    info->image.height = (uint32_t)height;
+    info->image.data_length = data_size;
+    info->hotspot_x = (int32_t)ii.xHotspot;
+    info->hotspot_y = (int32_t)ii.yHotspot;
+    info->visible = true;
+
+    return XCAP_OK;
+}
+
 // ============================================================================
 // Cleanup
 // ============================================================================
@@ -4,17 +4,18 @@ package windows
 
 /*
 #cgo CFLAGS: -DUNICODE -D_UNICODE
-#cgo LDFLAGS: -luser32 -lgdi32 -ldwmapi -lshcore -lpsapi
+#cgo LDFLAGS: -luser32 -lgdi32 -ldwmapi -lshcore -lpsapi -lsetupapi -ladvapi32
 
 #include "bridge.h"
 #include <stdlib.h>
 */
 import "C"
 import (
-	"errors"
 	"image"
 	"syscall"
 	"unsafe"
+
+	"github.com/zn-chen/xcap/internal/errcode"
 )
 
 // 错误码，与 bridge.h 中的定义对应
@@ -25,19 +26,38 @@ const (
 	errCaptureFailed = C.XCAP_ERR_CAPTURE_FAILED
 	errAllocFailed   = C.XCAP_ERR_ALLOC_FAILED
 	errNotFound      = C.XCAP_ERR_NOT_FOUND
+	errPermission    = C.XCAP_ERR_PERMISSION_DENIED
 )
 
-// ErrNotSupported 在功能未实现时返回
-var ErrNotSupported = errors.New("not supported")
+// 显示器连接方式，与 bridge.h 中的 XCAP_CONNECTOR_* 对应
+const (
+	ConnectorUnknown     = C.XCAP_CONNECTOR_UNKNOWN
+	ConnectorInternal    = C.XCAP_CONNECTOR_INTERNAL
+	ConnectorVGA         = C.XCAP_CONNECTOR_VGA
+	ConnectorDVI         = C.XCAP_CONNECTOR_DVI
+	ConnectorHDMI        = C.XCAP_CONNECTOR_HDMI
+	ConnectorDisplayPort = C.XCAP_CONNECTOR_DISPLAYPORT
+	ConnectorWireless    = C.XCAP_CONNECTOR_WIRELESS
+	ConnectorVirtual     = C.XCAP_CONNECTOR_VIRTUAL
+)
 
-// ErrCaptureFailed 在截图失败时返回
-var ErrCaptureFailed = errors.New("capture failed")
+// 窗口类型，与 bridge.h 中的 XCAP_WINDOW_TYPE_* 对应
+const (
+	WindowTypeUnknown = C.XCAP_WINDOW_TYPE_UNKNOWN
+	WindowTypeNormal  = C.XCAP_WINDOW_TYPE_NORMAL
+	WindowTypeDialog  = C.XCAP_WINDOW_TYPE_DIALOG
+	WindowTypeUtility = C.XCAP_WINDOW_TYPE_UTILITY
+	WindowTypeMenu    = C.XCAP_WINDOW_TYPE_MENU
+	WindowTypeTooltip = C.XCAP_WINDOW_TYPE_TOOLTIP
+	WindowTypeDock    = C.XCAP_WINDOW_TYPE_DOCK
+	WindowTypeDesktop = C.XCAP_WINDOW_TYPE_DESKTOP
+)
 
-// ErrNoMonitors 在没有找到显示器时返回
-var ErrNoMonitors = errors.New("no monitors found")
+// ErrNotSupported 在功能未实现时返回
+var ErrNotSupported = errcode.ErrNotSupported
 
-// ErrNoWindows 在没有找到窗口时返回
-var ErrNoWindows = errors.New("no windows found")
+// ErrInvalidRegion 在截图区域超出显示器范围时返回
+var ErrInvalidRegion = errcode.ErrInvalidRegion
 
 // HMONITOR 类型别名
 type HMONITOR uintptr
@@ -47,13 +67,23 @@ type HWND uintptr
 
 // MonitorInfo 表示从 C 层获取的显示器信息
 type MonitorInfo struct {
-	Handle  HMONITOR
-	Name    string
-	X       int32
-	Y       int32
-	Width   uint32
-	Height  uint32
-	Primary bool
+	Handle   HMONITOR
+	Name     string
+	X        int32
+	Y        int32
+	Width    uint32
+	Height   uint32
+	Primary  bool
+	Rotation float32
+
+	// Frequency 刷新率（Hz），未知时为 0
+	Frequency float32
+	// Connector 连接方式（Connector* 常量）
+	Connector int
+	// BitsPerPixel 当前显示模式的色深
+	BitsPerPixel uint32
+	// Model 显示器的友好名称（如 "DELL U2415"），未知时为空
+	Model string
 }
 
 // WindowInfo 表示从 C 层获取的窗口信息
@@ -68,6 +98,34 @@ type WindowInfo struct {
 	Height  uint32
 }
 
+// WindowBounds 表示窗口各区域的屏幕坐标（物理像素）
+type WindowBounds struct {
+	// Window 窗口矩形，包括不可见的缩放边框，即 CaptureWindow 截取的区域
+	Window image.Rectangle
+	// Frame 可见的窗口框架，包括标题栏和边框
+	Frame image.Rectangle
+	// Client 客户区
+	Client image.Rectangle
+}
+
+// WindowDetails 表示按需查询的窗口元数据
+type WindowDetails struct {
+	// Class 窗口类名
+	Class string
+	// Type 窗口类型（WindowType* 常量）
+	Type int
+	// Owner 所有者窗口，没有时为 0
+	Owner HWND
+	// Parent 父窗口，顶层窗口为 0
+	Parent HWND
+	// Modal 所有者在该窗口显示期间被禁用
+	Modal bool
+	// Opacity 不透明度，0 到 1
+	Opacity float32
+	// ProcessPath 进程可执行文件路径，无法访问时为空
+	ProcessPath string
+}
+
 // utf16ToString 将 UTF-16 数组转换为 Go 字符串
 func utf16ToString(s []uint16) string {
 	for i, v := range s {
@@ -86,10 +144,7 @@ func GetAllMonitors() ([]MonitorInfo, error) {
 
 	result := C.xcap_get_all_monitors(&cMonitors, &cCount)
 	if result != errOK {
-		if result == errNoMonitors {
-			return nil, ErrNoMonitors
-		}
-		return nil, errors.New("failed to get monitors")
+		return nil, &errcode.Error{Op: "get monitors", Code: errcode.Code(result)}
 	}
 	defer C.xcap_free_monitors(cMonitors)
 
@@ -111,14 +166,24 @@ func GetAllMonitors() ([]MonitorInfo, error) {
 			nameSlice[j] = uint16(cSlice[i].name[j])
 		}
 
+		modelSlice := make([]uint16, len(cSlice[i].model))
+		for j := range modelSlice {
+			modelSlice[j] = uint16(cSlice[i].model[j])
+		}
+
 		monitors[i] = MonitorInfo{
-			Handle:  HMONITOR(cSlice[i].handle),
-			Name:    utf16ToString(nameSlice),
-			X:       int32(cSlice[i].x),
-			Y:       int32(cSlice[i].y),
-			Width:   uint32(cSlice[i].width),
-			Height:  uint32(cSlice[i].height),
-			Primary: bool(cSlice[i].is_primary),
+			Handle:       HMONITOR(cSlice[i].handle),
+			Name:         utf16ToString(nameSlice),
+			X:            int32(cSlice[i].x),
+			Y:            int32(cSlice[i].y),
+			Width:        uint32(cSlice[i].width),
+			Height:       uint32(cSlice[i].height),
+			Primary:      bool(cSlice[i].is_primary),
+			Rotation:     float32(cSlice[i].rotation),
+			Frequency:    float32(cSlice[i].frequency),
+			Connector:    int(cSlice[i].connector),
+			BitsPerPixel: uint32(cSlice[i].bits_per_pixel),
+			Model:        utf16ToString(modelSlice),
 		}
 	}
 
@@ -138,10 +203,7 @@ func GetAllWindowsWithOptions(excludeCurrentProcess bool) ([]WindowInfo, error)
 
 	result := C.xcap_get_all_windows(&cWindows, &cCount, C.bool(excludeCurrentProcess))
 	if result != errOK {
-		if result == errNoWindows {
-			return nil, ErrNoWindows
-		}
-		return nil, errors.New("failed to get windows")
+		return nil, &errcode.Error{Op: "get windows", Code: errcode.Code(result)}
 	}
 	defer C.xcap_free_windows(cWindows)
 
@@ -157,28 +219,7 @@ func GetAllWindowsWithOptions(excludeCurrentProcess bool) ([]WindowInfo, error)
 	cSlice := unsafe.Slice(cWindows, count)
 
 	for i := 0; i < count; i++ {
-		// 将 UTF-16 app_name 转换为 Go 字符串
-		appNameSlice := make([]uint16, 260)
-		for j := 0; j < 260; j++ {
-			appNameSlice[j] = uint16(cSlice[i].app_name[j])
-		}
-
-		// 将 UTF-16 title 转换为 Go 字符串
-		titleSlice := make([]uint16, 256)
-		for j := 0; j < 256; j++ {
-			titleSlice[j] = uint16(cSlice[i].title[j])
-		}
-
-		windows[i] = WindowInfo{
-			Handle:  HWND(cSlice[i].handle),
-			PID:     uint32(cSlice[i].pid),
-			AppName: utf16ToString(appNameSlice),
-			Title:   utf16ToString(titleSlice),
-			X:       int32(cSlice[i].x),
-			Y:       int32(cSlice[i].y),
-			Width:   uint32(cSlice[i].width),
-			Height:  uint32(cSlice[i].height),
-		}
+		windows[i] = windowInfoFromC(&cSlice[i])
 	}
 
 	return windows, nil
@@ -197,7 +238,7 @@ func CaptureMonitor(info MonitorInfo) (*image.RGBA, error) {
 		&cResult,
 	)
 	if result != errOK {
-		return nil, ErrCaptureFailed
+		return nil, &errcode.Error{Op: "capture monitor", Code: errcode.Code(result)}
 	}
 	defer C.xcap_free_capture_result(&cResult)
 
@@ -210,7 +251,7 @@ func CaptureWindow(info WindowInfo) (*image.RGBA, error) {
 
 	result := C.xcap_capture_window(C.uintptr_t(info.Handle), &cResult)
 	if result != errOK {
-		return nil, ErrCaptureFailed
+		return nil, &errcode.Error{Op: "capture window", Code: errcode.Code(result)}
 	}
 	defer C.xcap_free_capture_result(&cResult)
 
@@ -240,6 +281,38 @@ func convertBGRAToRGBA(cResult *C.XcapCaptureResult) *image.RGBA {
 	return img
 }
 
+// GetCursorPosition 返回鼠标指针的全局坐标（物理像素）
+func GetCursorPosition() (int32, int32, error) {
+	var x, y C.int32_t
+
+	result := C.xcap_get_cursor_position(&x, &y)
+	if result != errOK {
+		return 0, 0, &errcode.Error{Op: "get cursor position", Code: errcode.Code(result)}
+	}
+
+	return int32(x), int32(y), nil
+}
+
+// CaptureCursor 返回当前鼠标指针的图像和热点
+func CaptureCursor() (*Cursor, error) {
+	var cInfo C.XcapCursorInfo
+
+	result := C.xcap_capture_cursor(&cInfo)
+	if result != errOK {
+		return nil, &errcode.Error{Op: "capture cursor", Code: errcode.Code(result)}
+	}
+	if !bool(cInfo.visible) {
+		return &Cursor{}, nil
+	}
+	defer C.xcap_free_capture_result(&cInfo.image)
+
+	return &Cursor{
+		Image:   convertBGRAToRGBA(&cInfo.image),
+		Hotspot: image.Pt(int(cInfo.hotspot_x), int(cInfo.hotspot_y)),
+		Visible: true,
+	}, nil
+}
+
 // IsWindowMinimized 检查窗口是否最小化
 func IsWindowMinimized(handle HWND) bool {
 	return bool(C.xcap_is_window_minimized(C.uintptr_t(handle)))
@@ -255,9 +328,114 @@ func IsWindowFocused(handle HWND) bool {
 	return bool(C.xcap_is_window_focused(C.uintptr_t(handle)))
 }
 
+// windowInfoFromC 将 C 层的窗口信息转换为 WindowInfo
+func windowInfoFromC(c *C.XcapWindowInfo) WindowInfo {
+	// 将 UTF-16 app_name 转换为 Go 字符串
+	appNameSlice := make([]uint16, 260)
+	for j := 0; j < 260; j++ {
+		appNameSlice[j] = uint16(c.app_name[j])
+	}
+
+	// 将 UTF-16 title 转换为 Go 字符串
+	titleSlice := make([]uint16, 256)
+	for j := 0; j < 256; j++ {
+		titleSlice[j] = uint16(c.title[j])
+	}
+
+	return WindowInfo{
+		Handle:  HWND(c.handle),
+		PID:     uint32(c.pid),
+		AppName: utf16ToString(appNameSlice),
+		Title:   utf16ToString(titleSlice),
+		X:       int32(c.x),
+		Y:       int32(c.y),
+		Width:   uint32(c.width),
+		Height:  uint32(c.height),
+	}
+}
+
+// GetWindowInfo 重新读取单个窗口的信息，窗口已销毁时返回 NotFound 错误
+func GetWindowInfo(handle HWND) (WindowInfo, error) {
+	var cInfo C.XcapWindowInfo
+
+	result := C.xcap_get_window_info(C.uintptr_t(handle), &cInfo)
+	if result != errOK {
+		return WindowInfo{}, &errcode.Error{Op: "get window info", Code: errcode.Code(result)}
+	}
+
+	return windowInfoFromC(&cInfo), nil
+}
+
+// GetWindowBounds 读取窗口各区域的位置，窗口已销毁时返回 NotFound 错误
+func GetWindowBounds(handle HWND) (WindowBounds, error) {
+	var cBounds C.XcapWindowBounds
+
+	result := C.xcap_get_window_bounds(C.uintptr_t(handle), &cBounds)
+	if result != errOK {
+		return WindowBounds{}, &errcode.Error{Op: "get window bounds", Code: errcode.Code(result)}
+	}
+
+	return WindowBounds{
+		Window: rectFromC(&cBounds.window),
+		Frame:  rectFromC(&cBounds.frame),
+		Client: rectFromC(&cBounds.client),
+	}, nil
+}
+
+// rectFromC 将 C 层的矩形转换为 image.Rectangle
+func rectFromC(r *C.XcapRect) image.Rectangle {
+	return image.Rect(int(r.left), int(r.top), int(r.right), int(r.bottom))
+}
+
+// GetWindowDetails 读取窗口的类名、类型、所有者等元数据，窗口已销毁时返回 NotFound 错误
+func GetWindowDetails(handle HWND) (WindowDetails, error) {
+	var cDetails C.XcapWindowDetails
+
+	result := C.xcap_get_window_details(C.uintptr_t(handle), &cDetails)
+	if result != errOK {
+		return WindowDetails{}, &errcode.Error{Op: "get window details", Code: errcode.Code(result)}
+	}
+
+	classSlice := make([]uint16, len(cDetails.class_name))
+	for i := range classSlice {
+		classSlice[i] = uint16(cDetails.class_name[i])
+	}
+	pathSlice := make([]uint16, len(cDetails.process_path))
+	for i := range pathSlice {
+		pathSlice[i] = uint16(cDetails.process_path[i])
+	}
+
+	return WindowDetails{
+		Class:       utf16ToString(classSlice),
+		Type:        int(cDetails.window_type),
+		Owner:       HWND(cDetails.owner),
+		Parent:      HWND(cDetails.parent),
+		Modal:       bool(cDetails.is_modal),
+		Opacity:     float32(cDetails.opacity),
+		ProcessPath: utf16ToString(pathSlice),
+	}, nil
+}
+
 // GetMonitorDPI 获取显示器 DPI
 func GetMonitorDPI(handle HMONITOR) (uint32, uint32) {
 	var dpiX, dpiY C.uint32_t
 	C.xcap_get_monitor_dpi(C.uintptr_t(handle), &dpiX, &dpiY)
 	return uint32(dpiX), uint32(dpiY)
 }
+
+// GetMonitorEDID 读取显示器的原始 EDID，系统没有记录 EDID 时返回 nil
+func GetMonitorEDID(handle HMONITOR) ([]byte, error) {
+	var cData *C.uint8_t
+	var cLength C.uint32_t
+
+	result := C.xcap_get_monitor_edid(C.uintptr_t(handle), &cData, &cLength)
+	if result == errNotFound {
+		return nil, nil
+	}
+	if result != errOK {
+		return nil, &errcode.Error{Op: "get monitor EDID", Code: errcode.Code(result)}
+	}
+	defer C.xcap_free_edid(cData)
+
+	return C.GoBytes(unsafe.Pointer(cData), C.int(cLength)), nil
+}
@@ -11,6 +11,27 @@
 #define XCAP_ERR_CAPTURE_FAILED 3
 #define XCAP_ERR_ALLOC_FAILED 4
 #define XCAP_ERR_NOT_FOUND 5
+#define XCAP_ERR_PERMISSION_DENIED 6
+
+// Monitor connector types
+#define XCAP_CONNECTOR_UNKNOWN 0
+#define XCAP_CONNECTOR_INTERNAL 1
+#define XCAP_CONNECTOR_VGA 2
+#define XCAP_CONNECTOR_DVI 3
+#define XCAP_CONNECTOR_HDMI 4
+#define XCAP_CONNECTOR_DISPLAYPORT 5
+#define XCAP_CONNECTOR_WIRELESS 6
+#define XCAP_CONNECTOR_VIRTUAL 7
+
+// Window types
+#define XCAP_WINDOW_TYPE_UNKNOWN 0
+#define XCAP_WINDOW_TYPE_NORMAL 1
+#define XCAP_WINDOW_TYPE_DIALOG 2
+#define XCAP_WINDOW_TYPE_UTILITY 3
+#define XCAP_WINDOW_TYPE_MENU 4
+#define XCAP_WINDOW_TYPE_TOOLTIP 5
+#define XCAP_WINDOW_TYPE_DOCK 6
+#define XCAP_WINDOW_TYPE_DESKTOP 7
 
 // Monitor information (using Windows native types)
 typedef struct {
@@ -21,6 +42,11 @@ typedef struct {
     uint32_t  width;
     uint32_t  height;
     bool      is_primary;
+    float     rotation;      // Clockwise degrees: 0, 90, 180 or 270
+    float     frequency;     // Refresh rate in Hz, 0 if unknown
+    int32_t   connector;     // XCAP_CONNECTOR_*
+    uint32_t  bits_per_pixel;
+    uint16_t  model[64];     // Monitor friendly name (UTF-16)
 } XcapMonitorInfo;
 
 // Window information
@@ -35,6 +61,32 @@ typedef struct {
     uint32_t  height;
 } XcapWindowInfo;
 
+// Rectangle in screen coordinates (physical pixels)
+typedef struct {
+    int32_t left;
+    int32_t top;
+    int32_t right;
+    int32_t bottom;
+} XcapRect;
+
+// Window areas
+typedef struct {
+    XcapRect window;         // GetWindowRect, includes the invisible resize borders
+    XcapRect frame;          // DWM extended frame bounds: title bar and visible border
+    XcapRect client;         // Client area
+} XcapWindowBounds;
+
+// Window metadata that is queried on demand rather than during enumeration
+typedef struct {
+    uint16_t  class_name[256];     // Window class (UTF-16)
+    int32_t   window_type;         // XCAP_WINDOW_TYPE_*
+    uintptr_t owner;               // Owner HWND, 0 if none
+    uintptr_t parent;              // Parent HWND, 0 for top-level windows
+    bool      is_modal;            // Owner is disabled while this window is shown
+    float     opacity;             // 0.0 - 1.0
+    uint16_t  process_path[1024];  // Executable path (UTF-16), empty if not accessible
+} XcapWindowDetails;
+
 // Capture result (BGRA pixel data)
 typedef struct {
     uint8_t  *data;
@@ -43,23 +95,40 @@ typedef struct {
     uint32_t  data_length;
 } XcapCaptureResult;
 
+// Cursor image (premultiplied BGRA pixel data) and hotspot
+typedef struct {
+    XcapCaptureResult image;
+    int32_t   hotspot_x;     // Hotspot in image pixels
+    int32_t   hotspot_y;
+    bool      visible;
+} XcapCursorInfo;
+
 // Monitor functions
 int xcap_get_all_monitors(XcapMonitorInfo **monitors, int *count);
 void xcap_free_monitors(XcapMonitorInfo *monitors);
 int xcap_capture_monitor(uintptr_t handle, int32_t x, int32_t y,
                          uint32_t width, uint32_t height, XcapCaptureResult *result);
 int xcap_get_monitor_dpi(uintptr_t handle, uint32_t *dpi_x, uint32_t *dpi_y);
+int xcap_get_monitor_edid(uintptr_t handle, uint8_t **data, uint32_t *length);
+void xcap_free_edid(uint8_t *data);
 
 // Window functions
 int xcap_get_all_windows(XcapWindowInfo **windows, int *count, bool exclude_current_process);
 void xcap_free_windows(XcapWindowInfo *windows);
 int xcap_capture_window(uintptr_t handle, XcapCaptureResult *result);
+int xcap_get_window_info(uintptr_t handle, XcapWindowInfo *info);
+int xcap_get_window_details(uintptr_t handle, XcapWindowDetails *details);
+int xcap_get_window_bounds(uintptr_t handle, XcapWindowBounds *bounds);
 
 // Window state functions
 bool xcap_is_window_minimized(uintptr_t handle);
 bool xcap_is_window_maximized(uintptr_t handle);
 bool xcap_is_window_focused(uintptr_t handle);
 
+// Cursor functions
+int xcap_get_cursor_position(int32_t *x, int32_t *y);
+int xcap_capture_cursor(XcapCursorInfo *info);
+
 // Capture cleanup
 void xcap_free_capture_result(XcapCaptureResult *result);
 
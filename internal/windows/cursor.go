@@ -0,0 +1,31 @@
+//go:build windows
+
+package windows
+
+import "image"
+
+// Cursor 表示 Windows 上的鼠标指针图像
+type Cursor struct {
+	// Image 指针图像（物理像素），Visible 为 false 时为 nil
+	Image *image.RGBA
+
+	// Hotspot 热点在 Image 中的像素坐标
+	Hotspot image.Point
+
+	// Visible 指针当前是否显示
+	Visible bool
+}
+
+// CursorPosition 返回鼠标指针的全局坐标（物理像素），与 Monitor.X()/Y() 同一坐标系
+func CursorPosition() (image.Point, error) {
+	x, y, err := GetCursorPosition()
+	if err != nil {
+		return image.Point{}, err
+	}
+	return image.Pt(int(x), int(y)), nil
+}
+
+// CursorImage 返回当前鼠标指针的图像，指针隐藏时 Visible 为 false
+func CursorImage() (*Cursor, error) {
+	return CaptureCursor()
+}
@@ -59,9 +59,9 @@ func (m *Monitor) Height() uint32 {
 	return m.info.Height
 }
 
-// Rotation 返回旋转角度
+// Rotation 返回画面相对帧缓冲的顺时针旋转角度（0、90、180、270）
 func (m *Monitor) Rotation() float32 {
-	return 0 // TODO: 通过 EnumDisplaySettings 实现
+	return m.info.Rotation
 }
 
 // ScaleFactor 返回 DPI 缩放因子
@@ -73,9 +73,9 @@ func (m *Monitor) ScaleFactor() float32 {
 	return 1.0
 }
 
-// Frequency 返回刷新率
+// Frequency 返回刷新率（Hz），未知时为 0
 func (m *Monitor) Frequency() float32 {
-	return 0 // TODO: 通过 EnumDisplaySettings 实现
+	return m.info.Frequency
 }
 
 // IsPrimary 返回是否为主显示器
@@ -83,9 +83,29 @@ func (m *Monitor) IsPrimary() bool {
 	return m.info.Primary
 }
 
-// IsBuiltin 返回是否为内置显示器
+// IsBuiltin 返回是否为内置显示器（笔记本面板等嵌入式连接）
 func (m *Monitor) IsBuiltin() bool {
-	return false // TODO: 实现
+	return m.info.Connector == ConnectorInternal
+}
+
+// Connector 返回连接方式（Connector* 常量）
+func (m *Monitor) Connector() int {
+	return m.info.Connector
+}
+
+// BitsPerPixel 返回当前显示模式的色深
+func (m *Monitor) BitsPerPixel() uint32 {
+	return m.info.BitsPerPixel
+}
+
+// Model 返回显示器的友好名称，未知时为空
+func (m *Monitor) Model() string {
+	return m.info.Model
+}
+
+// EDID 返回显示器的原始 EDID，系统没有记录时返回 nil
+func (m *Monitor) EDID() ([]byte, error) {
+	return GetMonitorEDID(m.info.Handle)
 }
 
 // CaptureImage 截取整个显示器，返回 RGBA 图像
@@ -93,7 +113,19 @@ func (m *Monitor) CaptureImage() (*image.RGBA, error) {
 	return CaptureMonitor(m.info)
 }
 
-// CaptureRegion 截取显示器的指定区域
+// CaptureRegion 截取显示器的指定区域（相对显示器左上角，物理像素）
 func (m *Monitor) CaptureRegion(x, y, width, height uint32) (*image.RGBA, error) {
-	return nil, ErrNotSupported // TODO: 实现
+	if width == 0 || height == 0 || uint64(x)+uint64(width) > uint64(m.info.Width) ||
+		uint64(y)+uint64(height) > uint64(m.info.Height) {
+		return nil, ErrInvalidRegion
+	}
+
+	// 复用整屏截图的 BitBlt 路径，只是把源矩形换成目标区域
+	region := m.info
+	region.X += int32(x)
+	region.Y += int32(y)
+	region.Width = width
+	region.Height = height
+
+	return CaptureMonitor(region)
 }
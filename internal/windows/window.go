@@ -7,6 +7,7 @@ import "image"
 // Window 表示 Windows 上的应用程序窗口
 type Window struct {
 	info WindowInfo
+	z    int
 }
 
 // NewWindow 从 WindowInfo 创建新的 Window
@@ -27,14 +28,26 @@ func AllWindowsWithOptions(excludeCurrentProcess bool) ([]*Window, error) {
 		return nil, err
 	}
 
+	// EnumWindows 按从前到后的顺序枚举顶层窗口
 	windows := make([]*Window, len(infos))
 	for i, info := range infos {
 		windows[i] = NewWindow(info)
+		windows[i].z = len(infos) - i
 	}
 
 	return windows, nil
 }
 
+// WindowByID 直接查询 ID 为 id 的窗口，不经过枚举的过滤，窗口不存在时返回 NotFound 错误
+// 单个窗口的查询没有 Z 顺序，Z 为 0
+func WindowByID(id uint32) (*Window, error) {
+	info, err := GetWindowInfo(HWND(id))
+	if err != nil {
+		return nil, err
+	}
+	return NewWindow(info), nil
+}
+
 // ID 返回窗口的唯一标识符
 func (w *Window) ID() uint32 {
 	return uint32(w.info.Handle)
@@ -65,9 +78,10 @@ func (w *Window) Y() int {
 	return int(w.info.Y)
 }
 
-// Z 返回窗口的 Z 顺序
+// Z 返回窗口的 Z 顺序，值越大越靠前，最前面的窗口等于枚举到的窗口总数
+// 只在同一次枚举得到的窗口之间可比较
 func (w *Window) Z() int {
-	return 0 // TODO: 通过 GetWindow 实现
+	return w.z
 }
 
 // Width 返回窗口的宽度（像素）
@@ -80,6 +94,16 @@ func (w *Window) Height() uint32 {
 	return w.info.Height
 }
 
+// Refresh 重新读取窗口的标题、位置和尺寸，返回新的 Window，Z 保持枚举时的值
+// 窗口已关闭时返回 NotFound 错误；ID 是否被其他进程的窗口复用由调用方比较 PID 判断
+func (w *Window) Refresh() (*Window, error) {
+	info, err := GetWindowInfo(w.info.Handle)
+	if err != nil {
+		return nil, err
+	}
+	return &Window{info: info, z: w.z}, nil
+}
+
 // IsMinimized 返回窗口是否最小化
 func (w *Window) IsMinimized() (bool, error) {
 	return IsWindowMinimized(w.info.Handle), nil
@@ -95,12 +119,67 @@ func (w *Window) IsFocused() (bool, error) {
 	return IsWindowFocused(w.info.Handle), nil
 }
 
+// Bounds 返回窗口各区域的当前位置
+func (w *Window) Bounds() (WindowBounds, error) {
+	return GetWindowBounds(w.info.Handle)
+}
+
+// Class 返回窗口类名，如 "Notepad"、"#32770"（对话框）
+func (w *Window) Class() (string, error) {
+	d, err := GetWindowDetails(w.info.Handle)
+	return d.Class, err
+}
+
+// Type 返回窗口类型（WindowType* 常量），根据窗口类和样式推断
+func (w *Window) Type() (int, error) {
+	d, err := GetWindowDetails(w.info.Handle)
+	return d.Type, err
+}
+
+// ProcessPath 返回所属进程的可执行文件路径
+// 无权查询该进程时返回 ErrNotSupported
+func (w *Window) ProcessPath() (string, error) {
+	d, err := GetWindowDetails(w.info.Handle)
+	if err != nil {
+		return "", err
+	}
+	if d.ProcessPath == "" {
+		return "", ErrNotSupported
+	}
+	return d.ProcessPath, nil
+}
+
+// OwnerID 返回所有者窗口的句柄，没有所有者时返回 0
+func (w *Window) OwnerID() (uint32, error) {
+	d, err := GetWindowDetails(w.info.Handle)
+	return uint32(d.Owner), err
+}
+
+// ParentID 返回父窗口的句柄，顶层窗口返回 0
+func (w *Window) ParentID() (uint32, error) {
+	d, err := GetWindowDetails(w.info.Handle)
+	return uint32(d.Parent), err
+}
+
+// IsModal 返回窗口是否为模态窗口，即所有者窗口在它显示期间被禁用
+func (w *Window) IsModal() (bool, error) {
+	d, err := GetWindowDetails(w.info.Handle)
+	return d.Modal, err
+}
+
+// Opacity 返回窗口的不透明度（0 到 1），只有分层窗口可能小于 1
+func (w *Window) Opacity() (float64, error) {
+	d, err := GetWindowDetails(w.info.Handle)
+	return float64(d.Opacity), err
+}
+
 // CurrentMonitor 返回窗口所在的显示器
 func (w *Window) CurrentMonitor() (*Monitor, error) {
 	return nil, ErrNotSupported // TODO: 通过 MonitorFromWindow 实现
 }
 
 // CaptureImage 截取窗口内容，返回 RGBA 图像
+// 图像覆盖包括不可见缩放边框在内的窗口矩形，即 WindowBounds.Window
 func (w *Window) CaptureImage() (*image.RGBA, error) {
 	return CaptureWindow(w.info)
 }
@@ -0,0 +1,97 @@
+// Package worker 提供在固定 OS 线程上串行执行函数的工作线程
+//
+// cgo 调用的 Win32 API 和 CoreGraphics 中有不少依赖线程状态，
+// 例如线程级 DPI 感知、GDI 对象的所属线程和 autorelease pool。
+// 把这些调用集中到同一个锁定的 OS 线程上执行，既避免了线程亲和性问题，
+// 也使原生调用互相串行，不需要在 C 层加锁。
+// 工作线程不是主线程，只能用于没有主线程要求的 API。
+//
+// 原生调用无法中途取消。DoContext 在 ctx 结束时只是不再等待结果，
+// 调用仍在工作线程上运行完毕，之后的请求继续排在它后面；
+// 因此任何时刻最多只有一个原生调用在运行，卡住的调用会让后续调用一直排队，
+// 带 ctx 的调用在排队超时后返回 ctx.Err()。
+package worker
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// Worker 是一个锁定在单个 OS 线程上的工作 goroutine
+// 零值可以直接使用，首次调用时启动，之后在进程退出前一直存在
+type Worker struct {
+	once sync.Once
+	reqs chan func()
+}
+
+func (w *Worker) start() {
+	w.reqs = make(chan func())
+	go w.loop()
+}
+
+// loop 在锁定的 OS 线程上依次执行请求
+func (w *Worker) loop() {
+	// 不调用 UnlockOSThread：线程与 Worker 同生命周期
+	runtime.LockOSThread()
+	for f := range w.reqs {
+		f()
+	}
+}
+
+// Do 在工作线程上执行 f 并等待其返回，多个 goroutine 的请求按到达顺序依次执行
+// f 中的 panic 会在调用方重新抛出。f 不能再调用同一个 Worker 的 Do，否则会死锁
+func (w *Worker) Do(f func()) {
+	_ = w.DoContext(context.Background(), f)
+}
+
+// DoContext 与 Do 相同，但在 ctx 结束时立即返回 ctx.Err()
+//
+// ctx 在 f 开始前结束（包括排队期间）时 f 不会执行。
+// f 已经开始执行时无法中断，DoContext 不再等待，f 在工作线程上运行完毕后结果被丢弃，
+// 在此之前后续请求继续排队。
+func (w *Worker) DoContext(ctx context.Context, f func()) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	w.once.Do(w.start)
+
+	// 缓冲为 1，放弃等待后 f 返回时不会阻塞工作线程
+	done := make(chan any, 1)
+	req := func() {
+		defer func() { done <- recover() }()
+		f()
+	}
+
+	select {
+	case w.reqs <- req:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case p := <-done:
+		if p != nil {
+			panic(p)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Call 在 w 的工作线程上执行 f，返回 f 的结果
+func Call[T any](w *Worker, f func() (T, error)) (T, error) {
+	return CallContext(context.Background(), w, f)
+}
+
+// CallContext 与 Call 相同，但在 ctx 结束时立即返回 ctx.Err()，见 DoContext
+func CallContext[T any](ctx context.Context, w *Worker, f func() (T, error)) (T, error) {
+	var v T
+	var err error
+	if cerr := w.DoContext(ctx, func() { v, err = f() }); cerr != nil {
+		var zero T
+		return zero, cerr
+	}
+	return v, err
+}
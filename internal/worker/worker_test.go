@@ -0,0 +1,140 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDoSerializes(t *testing.T) {
+	var w Worker
+	var running, maxRunning, calls int32
+
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				w.Do(func() {
+					n := atomic.AddInt32(&running, 1)
+					if n > atomic.LoadInt32(&maxRunning) {
+						atomic.StoreInt32(&maxRunning, n)
+					}
+					calls++ // 只在工作线程上修改，-race 下可以发现未串行的访问
+					atomic.AddInt32(&running, -1)
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxRunning != 1 {
+		t.Errorf("max concurrent calls = %d, want 1", maxRunning)
+	}
+	if calls != 32*50 {
+		t.Errorf("calls = %d, want %d", calls, 32*50)
+	}
+}
+
+func TestCall(t *testing.T) {
+	var w Worker
+	errFailed := errors.New("failed")
+
+	v, err := Call(&w, func() (int, error) { return 42, nil })
+	if v != 42 || err != nil {
+		t.Errorf("Call = %v, %v; want 42, nil", v, err)
+	}
+	if _, err := Call(&w, func() (int, error) { return 0, errFailed }); err != errFailed {
+		t.Errorf("Call error = %v, want %v", err, errFailed)
+	}
+}
+
+func TestDoPanic(t *testing.T) {
+	var w Worker
+
+	func() {
+		defer func() {
+			if p := recover(); p != "boom" {
+				t.Errorf("recovered %v, want boom", p)
+			}
+		}()
+		w.Do(func() { panic("boom") })
+	}()
+
+	// panic 之后工作线程仍可使用
+	ran := false
+	w.Do(func() { ran = true })
+	if !ran {
+		t.Error("worker stopped after panic")
+	}
+}
+
+func TestDoContextAbandonsBlockedCall(t *testing.T) {
+	var w Worker
+	release := make(chan struct{})
+	finished := make(chan struct{})
+
+	// 模拟卡住的原生调用
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := w.DoContext(ctx, func() {
+		<-release
+		close(finished)
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("DoContext = %v, want deadline exceeded", err)
+	}
+
+	// 放弃等待不会另起线程：后续调用排在卡住的调用之后，带 ctx 的调用超时返回
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel2()
+	if _, err := CallContext(ctx2, &w, func() (int, error) { return 7, nil }); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("CallContext behind blocked call = %v, want deadline exceeded", err)
+	}
+
+	// 卡住的调用返回后，同一个线程继续处理后续调用，两者不会重叠
+	close(release)
+	v, err := Call(&w, func() (int, error) {
+		select {
+		case <-finished:
+		default:
+			t.Error("later call overlapped the abandoned call")
+		}
+		return 7, nil
+	})
+	if v != 7 || err != nil {
+		t.Errorf("Call = %v, %v", v, err)
+	}
+}
+
+func TestDoContextWhileQueued(t *testing.T) {
+	var w Worker
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go w.Do(func() {
+		close(started)
+		<-release
+	})
+	<-started
+	defer close(release)
+
+	// 排队等待时 ctx 结束，f 不会执行
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	ran := false
+	if err := w.DoContext(ctx, func() { ran = true }); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("DoContext = %v, want deadline exceeded", err)
+	}
+
+	// 已结束的 ctx 不发起调用
+	if _, err := CallContext(ctx, &w, func() (int, error) { ran = true; return 0, nil }); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("CallContext = %v, want deadline exceeded", err)
+	}
+	if ran {
+		t.Error("f ran after ctx ended")
+	}
+}
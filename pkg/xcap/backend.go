@@ -0,0 +1,40 @@
+package xcap
+
+import (
+	"context"
+	"image"
+)
+
+// backend 是平台相关实现的入口
+// 各平台文件提供 nativeBackend，测试中替换为假实现
+type backend interface {
+	// allMonitors、allWindows 在 ctx 结束时立即返回 ctx.Err()
+	allMonitors(ctx context.Context) ([]Monitor, error)
+	allWindows(ctx context.Context, excludeCurrentProcess bool) ([]Window, error)
+	// windowByID 直接查询单个窗口，不经过 allWindows 的过滤
+	windowByID(id uint32) (Window, error)
+	checkPermission() PermissionStatus
+	requestPermission() PermissionStatus
+	cursorPosition() (image.Point, error)
+	cursorImage() (*Cursor, error)
+	coordinateSpace() Space
+}
+
+// platform 是当前使用的后端
+var platform backend = nativeBackend{}
+
+// AllMonitors 返回系统上所有可用的显示器
+func AllMonitors() ([]Monitor, error) {
+	return platform.allMonitors(context.Background())
+}
+
+// AllWindows 返回系统上所有可见的窗口（包括当前进程的窗口）
+func AllWindows() ([]Window, error) {
+	return AllWindowsWithOptions(false)
+}
+
+// AllWindowsWithOptions 返回系统上所有可见的窗口
+// excludeCurrentProcess: 是否排除当前进程的窗口
+func AllWindowsWithOptions(excludeCurrentProcess bool) ([]Window, error) {
+	return platform.allWindows(context.Background(), excludeCurrentProcess)
+}
@@ -0,0 +1,162 @@
+package xcap
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math"
+)
+
+// 颜色取样与对比度计算
+//
+// 截图的像素值均按 sRGB 解释：macOS 后端在截图时转换到 sRGB 色彩空间，
+// Windows 的 GDI 截图本身即为 sRGB。
+
+// PixelAt 返回全局坐标 (globalX, globalY) 处的像素颜色
+// 坐标系与 Monitor.X()/Y() 相同；不在任何显示器内时返回 ErrNoMonitor
+func PixelAt(globalX, globalY int) (color.RGBA, error) {
+	monitors, err := AllMonitors()
+	if err != nil {
+		return color.RGBA{}, err
+	}
+
+	m := monitorAt(monitors, image.Pt(globalX, globalY))
+	if m == nil {
+		return color.RGBA{}, ErrNoMonitor
+	}
+
+	return m.PixelAt(globalX-m.X(), globalY-m.Y())
+}
+
+// SampleColors 返回多个全局坐标处的像素颜色，结果与 points 一一对应
+// 每个涉及的显示器只截图一次；任一点不在显示器内时返回 ErrNoMonitor
+func SampleColors(points []image.Point) ([]color.RGBA, error) {
+	monitors, err := AllMonitors()
+	if err != nil {
+		return nil, err
+	}
+
+	captures := make(map[Monitor]*image.RGBA)
+	colors := make([]color.RGBA, len(points))
+
+	for i, p := range points {
+		m := monitorAt(monitors, p)
+		if m == nil {
+			return nil, ErrNoMonitor
+		}
+
+		img, ok := captures[m]
+		if !ok {
+			img, err = m.CaptureImage()
+			if err != nil {
+				return nil, err
+			}
+			captures[m] = img
+		}
+
+		colors[i] = img.RGBAAt(scaleToImage(img, p.X-m.X(), p.Y-m.Y(), m.Width(), m.Height()))
+	}
+
+	return colors, nil
+}
+
+// monitorPixelAt 是 Monitor.PixelAt 的通用实现
+// 优先使用 1x1 的区域截图，后端不支持区域截图时退回到整屏截图
+func monitorPixelAt(m Monitor, x, y int) (color.RGBA, error) {
+	if x < 0 || y < 0 || x >= int(m.Width()) || y >= int(m.Height()) {
+		return color.RGBA{}, ErrInvalidRegion
+	}
+
+	// 高 DPI 显示器上 1x1 点的区域截图可能包含多个像素，取左上角的像素
+	img, err := m.CaptureRegion(uint32(x), uint32(y), 1, 1)
+	if err == nil {
+		return img.RGBAAt(img.Bounds().Min.X, img.Bounds().Min.Y), nil
+	}
+	if !errors.Is(err, ErrNotSupported) {
+		return color.RGBA{}, err
+	}
+
+	img, err = m.CaptureImage()
+	if err != nil {
+		return color.RGBA{}, err
+	}
+	return img.RGBAAt(scaleToImage(img, x, y, m.Width(), m.Height())), nil
+}
+
+// monitorAt 返回包含全局坐标 p 的显示器，没有时返回 nil
+func monitorAt(monitors []Monitor, p image.Point) Monitor {
+	for _, m := range monitors {
+		if p.In(image.Rect(m.X(), m.Y(), m.X()+int(m.Width()), m.Y()+int(m.Height()))) {
+			return m
+		}
+	}
+	return nil
+}
+
+// scaleToImage 将几何坐标（与 Width/Height 同一坐标系）换算为截图中的像素坐标
+func scaleToImage(img *image.RGBA, x, y int, width, height uint32) (int, int) {
+	b := img.Bounds()
+	if width > 0 && height > 0 {
+		x = x * b.Dx() / int(width)
+		y = y * b.Dy() / int(height)
+	}
+	return b.Min.X + x, b.Min.Y + y
+}
+
+// RelativeLuminance 返回 sRGB 颜色的相对亮度（WCAG 2.x 定义，0-1）
+func RelativeLuminance(c color.Color) float64 {
+	r, g, b, _ := c.RGBA()
+
+	linear := func(v uint32) float64 {
+		s := float64(v) / 0xFFFF
+		if s <= 0.04045 {
+			return s / 12.92
+		}
+		return math.Pow((s+0.055)/1.055, 2.4)
+	}
+
+	return 0.2126*linear(r) + 0.7152*linear(g) + 0.0722*linear(b)
+}
+
+// ContrastRatio 返回两个颜色的 WCAG 对比度（1-21）
+// WCAG AA 要求普通文本至少 4.5，大号文本至少 3
+func ContrastRatio(a, b color.Color) float64 {
+	la, lb := RelativeLuminance(a), RelativeLuminance(b)
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// AverageColor 返回 img 中区域 r 的平均颜色，r 会被裁剪到图像范围内
+func AverageColor(img *image.RGBA, r image.Rectangle) color.RGBA {
+	r = r.Intersect(img.Bounds())
+	if r.Empty() {
+		return color.RGBA{}
+	}
+
+	var sum [4]uint64
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		row := img.Pix[img.PixOffset(r.Min.X, y):img.PixOffset(r.Max.X, y)]
+		for i := 0; i < len(row); i += 4 {
+			sum[0] += uint64(row[i+0])
+			sum[1] += uint64(row[i+1])
+			sum[2] += uint64(row[i+2])
+			sum[3] += uint64(row[i+3])
+		}
+	}
+
+	n := uint64(r.Dx() * r.Dy())
+	return color.RGBA{
+		R: uint8((sum[0] + n/2) / n),
+		G: uint8((sum[1] + n/2) / n),
+		B: uint8((sum[2] + n/2) / n),
+		A: uint8((sum[3] + n/2) / n),
+	}
+}
+
+// RegionContrastRatio 返回 img 中两个区域平均颜色之间的 WCAG 对比度
+// 例如前景取文字所在区域、背景取其周围的区域
+func RegionContrastRatio(img *image.RGBA, fg, bg image.Rectangle) float64 {
+	return ContrastRatio(AverageColor(img, fg), AverageColor(img, bg))
+}
@@ -0,0 +1,64 @@
+package xcap
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestContrastRatio(t *testing.T) {
+	black := color.RGBA{0, 0, 0, 255}
+	white := color.RGBA{255, 255, 255, 255}
+
+	if got := ContrastRatio(black, white); math.Abs(got-21) > 1e-9 {
+		t.Errorf("ContrastRatio(black, white) = %v, want 21", got)
+	}
+	if got := ContrastRatio(white, black); math.Abs(got-21) > 1e-9 {
+		t.Errorf("ContrastRatio(white, black) = %v, want 21", got)
+	}
+
+	gray := color.RGBA{0x77, 0x77, 0x77, 255}
+	if got := ContrastRatio(gray, gray); got != 1 {
+		t.Errorf("ContrastRatio(gray, gray) = %v, want 1", got)
+	}
+	// #777 白底是常见的 WCAG AA 临界值（约 4.48）
+	if got := ContrastRatio(gray, white); got < 4.47 || got > 4.49 {
+		t.Errorf("ContrastRatio(#777, white) = %v, want ~4.48", got)
+	}
+}
+
+func TestAverageColor(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	fillRect(img, image.Rect(0, 0, 2, 2), color.RGBA{200, 0, 0, 255})
+	fillRect(img, image.Rect(2, 0, 4, 2), color.RGBA{0, 100, 0, 255})
+
+	if got, want := AverageColor(img, img.Bounds()), (color.RGBA{100, 50, 0, 255}); got != want {
+		t.Errorf("AverageColor = %v, want %v", got, want)
+	}
+	if got := AverageColor(img, image.Rect(10, 10, 20, 20)); got != (color.RGBA{}) {
+		t.Errorf("AverageColor outside image = %v, want zero", got)
+	}
+}
+
+func TestMonitorPixelAt(t *testing.T) {
+	// 2x 缩放：几何尺寸 4x4，截图 8x8 像素
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	fillRect(img, image.Rect(4, 2, 6, 4), color.RGBA{10, 20, 30, 255})
+
+	for _, regionSupported := range []bool{false, true} {
+		m := &fakeMonitor{width: 4, height: 4, scaleFactor: 2, img: img, regionSupported: regionSupported}
+
+		c, err := m.PixelAt(2, 1)
+		if err != nil {
+			t.Fatalf("PixelAt (region=%v) failed: %v", regionSupported, err)
+		}
+		if want := (color.RGBA{10, 20, 30, 255}); c != want {
+			t.Errorf("PixelAt(2, 1) (region=%v) = %v, want %v", regionSupported, c, want)
+		}
+
+		if _, err := m.PixelAt(4, 0); err != ErrInvalidRegion {
+			t.Errorf("PixelAt out of bounds (region=%v): err = %v, want ErrInvalidRegion", regionSupported, err)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package xcap
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"sort"
+)
+
+// Layer 描述合成时的一个窗口图层
+type Layer struct {
+	// Window 图层对应的窗口，提供位置、尺寸和 Z 顺序
+	Window Window
+
+	// Image 窗口截图，为 nil 时调用 Window.CaptureImage()
+	Image *image.RGBA
+
+	// Offset 相对窗口原位置的平移（全局坐标），用于移动窗口
+	Offset image.Point
+
+	// Hidden 为 true 时不绘制该图层
+	Hidden bool
+
+	// Opacity 不透明度（0-1），为 0 时视为 1；需要隐藏窗口时请使用 Hidden
+	Opacity float64
+
+	// Border 窗口边框，为 nil 时不绘制
+	Border *Border
+
+	// Shadow 窗口阴影，为 nil 时不绘制
+	Shadow *Shadow
+}
+
+// Border 描述绘制在窗口外侧的边框，常用于高亮某个窗口
+type Border struct {
+	// Width 边框宽度（画布像素）
+	Width int
+
+	// Color 边框颜色
+	Color color.Color
+}
+
+// Shadow 描述窗口阴影
+type Shadow struct {
+	// Offset 阴影相对窗口的偏移（画布像素）
+	Offset image.Point
+
+	// Blur 阴影模糊的标准差（画布像素），为 0 时为硬阴影
+	Blur float64
+
+	// Color 阴影颜色，通常为半透明黑色
+	Color color.Color
+}
+
+// Compositor 将多个窗口的单独截图按 Z 顺序合成为一张图像
+//
+// 与直接截取显示器不同，合成结果只包含给定的窗口，并且可以在合成前
+// 移动、隐藏或高亮其中的窗口，适合生成经过隐私过滤或标注的截图。
+type Compositor struct {
+	// Area 画布覆盖的全局坐标区域
+	Area image.Rectangle
+
+	// Scale 每个全局坐标单位对应的画布像素数。为 0 时取各图层截图与窗口尺寸之比的最大值，
+	// 例如 macOS Retina 屏幕上为 2，以保留窗口截图的分辨率
+	Scale float64
+
+	// Background 画布背景色，为 nil 时透明
+	Background color.Color
+}
+
+// NewMonitorCompositor 返回画布与显示器大小相同的 Compositor
+func NewMonitorCompositor(m Monitor) *Compositor {
+	return &Compositor{Area: monitorRect(m)}
+}
+
+// ComposeMonitor 用 windows 的单独截图在显示器大小的画布上重建显示器画面
+// 无法截取的窗口（如已最小化）会被跳过
+func ComposeMonitor(m Monitor, windows []Window) (*image.RGBA, error) {
+	layers := make([]Layer, 0, len(windows))
+	for _, w := range windows {
+		if !windowRect(w).Overlaps(monitorRect(m)) {
+			continue
+		}
+		img, err := w.CaptureImage()
+		if err != nil {
+			continue
+		}
+		layers = append(layers, Layer{Window: w, Image: img})
+	}
+
+	return NewMonitorCompositor(m).Compose(layers)
+}
+
+// Compose 按 Z 顺序从后到前绘制 layers，返回的图像 Bounds() 从 (0, 0) 开始，对应 Area 的左上角
+// Z 相同的图层按 layers 中的顺序视为从前到后，与 AllWindows() 的返回顺序一致
+func (c *Compositor) Compose(layers []Layer) (*image.RGBA, error) {
+	if c.Area.Empty() {
+		return nil, ErrInvalidRegion
+	}
+
+	// 补齐截图并按 Z 从前到后排序
+	visible := make([]Layer, 0, len(layers))
+	for _, l := range layers {
+		if l.Hidden {
+			continue
+		}
+		if l.Image == nil {
+			img, err := l.Window.CaptureImage()
+			if err != nil {
+				return nil, err
+			}
+			l.Image = img
+		}
+		visible = append(visible, l)
+	}
+	sort.SliceStable(visible, func(i, j int) bool {
+		return visible[i].Window.Z() > visible[j].Window.Z()
+	})
+
+	scale := c.Scale
+	if scale <= 0 {
+		scale = 1
+		for _, l := range visible {
+			if w := l.Window.Width(); w > 0 {
+				scale = math.Max(scale, float64(l.Image.Bounds().Dx())/float64(w))
+			}
+		}
+	}
+
+	canvas := image.NewRGBA(image.Rect(0, 0,
+		int(math.Ceil(float64(c.Area.Dx())*scale)),
+		int(math.Ceil(float64(c.Area.Dy())*scale)),
+	))
+	if c.Background != nil {
+		draw.Draw(canvas, canvas.Bounds(), &image.Uniform{C: c.Background}, image.Point{}, draw.Src)
+	}
+
+	// 从后往前绘制
+	for i := len(visible) - 1; i >= 0; i-- {
+		l := visible[i]
+
+		// 不裁剪到画布，部分在画布外的窗口按完整尺寸缩放后再由 draw 裁剪
+		r := scaleRect(windowRect(l.Window).Add(l.Offset), c.Area, canvas.Bounds())
+		if r.Empty() {
+			continue
+		}
+
+		if l.Shadow != nil {
+			drawShadow(canvas, r, l.Shadow)
+		}
+		drawLayer(canvas, r, l.Image, l.Opacity)
+		if l.Border != nil && l.Border.Width > 0 {
+			drawBorder(canvas, r, l.Border)
+		}
+	}
+
+	return canvas, nil
+}
+
+// drawLayer 将 src 缩放到 r 并以 opacity 叠加到 dst 上
+func drawLayer(dst *image.RGBA, r image.Rectangle, src *image.RGBA, opacity float64) {
+	if src.Bounds().Dx() != r.Dx() || src.Bounds().Dy() != r.Dy() {
+		scaled := image.NewRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+		drawScaled(scaled, scaled.Bounds(), src)
+		src = scaled
+	}
+
+	if opacity <= 0 || opacity >= 1 {
+		draw.Draw(dst, r, src, src.Bounds().Min, draw.Over)
+		return
+	}
+	mask := &image.Uniform{C: color.Alpha{A: uint8(math.Round(opacity * 255))}}
+	draw.DrawMask(dst, r, src, src.Bounds().Min, mask, image.Point{}, draw.Over)
+}
+
+// drawShadow 在窗口矩形 r 下方绘制阴影
+func drawShadow(dst *image.RGBA, r image.Rectangle, s *Shadow) {
+	c := s.Color
+	if c == nil {
+		c = color.RGBA{A: 128}
+	}
+
+	pad := int(math.Ceil(s.Blur * 3))
+	shadow := r.Add(s.Offset)
+	mask := image.NewRGBA(shadow.Inset(-pad))
+	draw.Draw(mask, shadow, &image.Uniform{C: c}, image.Point{}, draw.Src)
+	if s.Blur > 0 {
+		gaussianBlur(mask, mask.Bounds(), s.Blur)
+	}
+
+	draw.Draw(dst, mask.Bounds(), mask, mask.Bounds().Min, draw.Over)
+}
+
+// drawBorder 在窗口矩形 r 外侧绘制边框
+func drawBorder(dst *image.RGBA, r image.Rectangle, b *Border) {
+	c := b.Color
+	if c == nil {
+		c = color.RGBA{R: 255, A: 255}
+	}
+
+	src := &image.Uniform{C: c}
+	outer := r.Inset(-b.Width)
+	for _, s := range []image.Rectangle{
+		image.Rect(outer.Min.X, outer.Min.Y, outer.Max.X, r.Min.Y),
+		image.Rect(outer.Min.X, r.Max.Y, outer.Max.X, outer.Max.Y),
+		image.Rect(outer.Min.X, r.Min.Y, r.Min.X, r.Max.Y),
+		image.Rect(r.Max.X, r.Min.Y, outer.Max.X, r.Max.Y),
+	} {
+		draw.Draw(dst, s, src, image.Point{}, draw.Over)
+	}
+}
@@ -0,0 +1,77 @@
+package xcap
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestCompositorZOrder(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	green := color.RGBA{0, 255, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	white := color.RGBA{255, 255, 255, 255}
+
+	// 2x 截图：窗口几何 20x20，截图 40x40
+	back := &fakeWindow{id: 1, x: 10, y: 10, z: 1, width: 20, height: 20, img: solidImage(40, 40, red)}
+	front := &fakeWindow{id: 2, x: 20, y: 20, z: 2, width: 20, height: 20, img: solidImage(40, 40, green)}
+	hidden := &fakeWindow{id: 3, x: 0, y: 0, z: 3, width: 50, height: 50, img: solidImage(100, 100, blue)}
+
+	c := &Compositor{Area: image.Rect(0, 0, 50, 50), Background: white}
+	img, err := c.Compose([]Layer{
+		{Window: front},
+		{Window: hidden, Hidden: true},
+		{Window: back, Border: &Border{Width: 2, Color: blue}},
+	})
+	if err != nil {
+		t.Fatalf("Compose failed: %v", err)
+	}
+	if want := image.Rect(0, 0, 100, 100); img.Bounds() != want {
+		t.Fatalf("bounds = %v, want %v (scale derived from captures)", img.Bounds(), want)
+	}
+
+	for _, p := range []struct {
+		x, y int
+		want color.RGBA
+	}{
+		{5, 5, white},   // 背景
+		{25, 25, red},   // 后面的窗口
+		{45, 45, green}, // 重叠处由 Z 较大的窗口覆盖
+		{19, 30, blue},  // 后面窗口的边框
+		{17, 30, white}, // 边框外
+		{79, 79, green},
+	} {
+		if got := img.RGBAAt(p.x, p.y); got != p.want {
+			t.Errorf("pixel (%d, %d) = %v, want %v", p.x, p.y, got, p.want)
+		}
+	}
+}
+
+func TestCompositorOffsetOpacityShadow(t *testing.T) {
+	w := &fakeWindow{x: 0, y: 0, width: 10, height: 10, img: solidImage(10, 10, color.RGBA{255, 255, 255, 255})}
+
+	c := &Compositor{Area: image.Rect(0, 0, 40, 40), Scale: 1, Background: color.RGBA{A: 255}}
+	img, err := c.Compose([]Layer{{
+		Window:  w,
+		Offset:  image.Pt(10, 10),
+		Opacity: 0.5,
+		Shadow:  &Shadow{Offset: image.Pt(5, 5), Blur: 1, Color: color.RGBA{R: 200, A: 255}},
+	}})
+	if err != nil {
+		t.Fatalf("Compose failed: %v", err)
+	}
+
+	if got := img.RGBAAt(5, 5); got != (color.RGBA{A: 255}) {
+		t.Errorf("original position = %v, want background", got)
+	}
+	if got := img.RGBAAt(12, 12); got.R < 126 || got.R > 129 || got.G != got.R {
+		t.Errorf("half-transparent window = %v, want ~50%% gray", got)
+	}
+	if got := img.RGBAAt(23, 23); got.R < 150 || got.G != 0 {
+		t.Errorf("shadow = %v, want red shadow", got)
+	}
+
+	if _, err := (&Compositor{}).Compose(nil); err != ErrInvalidRegion {
+		t.Errorf("empty area: err = %v, want ErrInvalidRegion", err)
+	}
+}
@@ -0,0 +1,54 @@
+package xcap
+
+import (
+	"context"
+	"image/color"
+	"sync"
+	"testing"
+)
+
+// TestConcurrentCapture 在多个 goroutine 中同时枚举和截图，配合 -race 检查公共 API 是否存在数据竞争
+func TestConcurrentCapture(t *testing.T) {
+	m := &fakeMonitor{id: 1, width: 100, height: 100, scaleFactor: 1, img: solidImage(100, 100, color.RGBA{B: 255, A: 255}), regionSupported: true}
+	front := &fakeWindow{id: 1, x: 10, y: 10, z: 2, width: 40, height: 40, img: solidImage(40, 40, color.RGBA{R: 255, A: 255})}
+	back := &fakeWindow{id: 2, x: 30, y: 30, z: 1, width: 40, height: 40, img: solidImage(40, 40, color.RGBA{G: 255, A: 255})}
+	useFakeBackend(t, &fakeBackend{monitors: []Monitor{m}, windows: []Window{front, back}})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 64)
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				var err error
+				switch (i + j) % 6 {
+				case 0:
+					_, err = AllMonitors()
+				case 1:
+					_, err = AllWindowsContext(context.Background())
+				case 2:
+					_, err = m.CaptureImageContext(context.Background())
+				case 3:
+					_, err = back.VisibleRegion()
+				case 4:
+					_, err = CaptureMonitorExcluding(m, []Window{front})
+				case 5:
+					_, err = CaptureDesktop()
+				}
+				if err != nil {
+					select {
+					case errs <- err:
+					default:
+					}
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
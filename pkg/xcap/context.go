@@ -0,0 +1,13 @@
+package xcap
+
+import "context"
+
+// AllMonitorsContext 与 AllMonitors 相同，但在 ctx 取消或超时时立即返回 ctx.Err()
+func AllMonitorsContext(ctx context.Context) ([]Monitor, error) {
+	return platform.allMonitors(ctx)
+}
+
+// AllWindowsContext 与 AllWindows 相同，但在 ctx 取消或超时时立即返回 ctx.Err()
+func AllWindowsContext(ctx context.Context) ([]Window, error) {
+	return platform.allWindows(ctx, false)
+}
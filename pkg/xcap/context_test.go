@@ -0,0 +1,66 @@
+package xcap
+
+import (
+	"context"
+	"errors"
+	"image/color"
+	"testing"
+	"time"
+)
+
+func TestCaptureImageContextTimeout(t *testing.T) {
+	m := &fakeMonitor{width: 10, height: 10, img: solidImage(10, 10, color.RGBA{A: 255}), delay: time.Second}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	img, err := m.CaptureImageContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) || img != nil {
+		t.Fatalf("expected deadline exceeded, got %v, %v", img, err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("CaptureImageContext returned after %v, want it to abandon the slow call", elapsed)
+	}
+
+	m = &fakeMonitor{width: 10, height: 10, img: solidImage(10, 10, color.RGBA{A: 255})}
+	if img, err := m.CaptureImageContext(context.Background()); err != nil || img == nil {
+		t.Errorf("CaptureImageContext without deadline failed: %v", err)
+	}
+}
+
+func TestCaptureImageContextCanceled(t *testing.T) {
+	w := &fakeWindow{width: 10, height: 10, img: solidImage(10, 10, color.RGBA{A: 255}), delay: time.Second}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	if _, err := w.CaptureImageContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	// 已取消的 ctx 不应再发起调用
+	w = &fakeWindow{err: errors.New("should not be called")}
+	if _, err := w.CaptureImageContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled for canceled ctx, got %v", err)
+	}
+}
+
+func TestAllWindowsContext(t *testing.T) {
+	b := &fakeBackend{windows: []Window{&fakeWindow{id: 1}}, delay: time.Second}
+	useFakeBackend(t, b)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := AllWindowsContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	useFakeBackend(t, &fakeBackend{windows: []Window{&fakeWindow{id: 1}}})
+	windows, err := AllWindowsContext(context.Background())
+	if err != nil || len(windows) != 1 {
+		t.Errorf("AllWindowsContext = %v, %v; want 1 window", windows, err)
+	}
+}
@@ -0,0 +1,77 @@
+package xcap
+
+import (
+	"image"
+	"math"
+)
+
+// Cursor 描述鼠标指针的图像
+type Cursor struct {
+	// Image 指针图像（预乘 alpha），Visible 为 false 时为 nil
+	Image *image.RGBA
+
+	// Hotspot 热点在 Image 中的像素坐标，即指针实际指向的位置
+	Hotspot image.Point
+
+	// Scale Image 中每个全局坐标单位对应的像素数，如 macOS Retina 光标为 2
+	Scale float64
+
+	// Visible 指针当前是否显示（例如 Windows 上输入文字时指针会被隐藏）
+	Visible bool
+}
+
+// CursorPosition 返回鼠标指针热点的全局坐标，与 Monitor.X()/Y() 同一坐标系
+func CursorPosition() (image.Point, error) {
+	return platform.cursorPosition()
+}
+
+// CursorImage 返回当前鼠标指针的图像和热点
+// macOS 上指针图像只能在主线程上读取，主线程没有运行 main queue
+// （例如没有启动 NSApplication 的命令行程序）时返回 ErrNotSupported
+func CursorImage() (*Cursor, error) {
+	return platform.cursorImage()
+}
+
+// overlayCursor 把当前鼠标指针绘制到覆盖全局区域 area 的截图 img 上
+// 指针隐藏或不在 area 内时不做任何修改
+func overlayCursor(img *image.RGBA, area image.Rectangle) error {
+	pos, err := CursorPosition()
+	if err != nil {
+		return err
+	}
+	if !pos.In(area) {
+		return nil
+	}
+
+	cursor, err := CursorImage()
+	if err != nil {
+		return err
+	}
+	drawCursor(img, area, pos, cursor)
+	return nil
+}
+
+// drawCursor 将 cursor 按截图的缩放比例绘制到 img 上，使热点对齐全局坐标 pos
+func drawCursor(img *image.RGBA, area image.Rectangle, pos image.Point, cursor *Cursor) {
+	if !cursor.Visible || cursor.Image == nil || area.Empty() {
+		return
+	}
+
+	cs := cursor.Scale
+	if cs <= 0 {
+		cs = 1
+	}
+	b := img.Bounds()
+	scale := float64(b.Dx()) / float64(area.Dx())
+	k := scale / cs
+
+	x := float64(pos.X-area.Min.X)*scale - float64(cursor.Hotspot.X)*k
+	y := float64(pos.Y-area.Min.Y)*scale - float64(cursor.Hotspot.Y)*k
+	r := image.Rect(
+		int(math.Round(x)), int(math.Round(y)),
+		int(math.Round(x+float64(cursor.Image.Bounds().Dx())*k)),
+		int(math.Round(y+float64(cursor.Image.Bounds().Dy())*k)),
+	).Add(b.Min)
+
+	drawLayer(img, r, cursor.Image, 1)
+}
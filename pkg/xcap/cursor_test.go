@@ -0,0 +1,87 @@
+package xcap
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestCaptureMonitorIncludeCursor(t *testing.T) {
+	blue := color.RGBA{B: 255, A: 255}
+	red := color.RGBA{R: 255, A: 255}
+
+	// 2x 缩放的显示器，几何 100x100；1x 指针 4x4，热点 (1, 1)
+	m := &fakeMonitor{x: 100, width: 100, height: 100, scaleFactor: 2, img: solidImage(200, 200, blue)}
+	cursor := &Cursor{Image: solidImage(4, 4, red), Hotspot: image.Pt(1, 1), Scale: 1, Visible: true}
+	b := &fakeBackend{monitors: []Monitor{m}, cursorPos: image.Pt(150, 50), cursor: cursor}
+	useFakeBackend(t, b)
+
+	img, err := CaptureMonitorWithOptions(m, CaptureOptions{IncludeCursor: true})
+	if err != nil {
+		t.Fatalf("CaptureMonitorWithOptions failed: %v", err)
+	}
+
+	// 热点 (50, 50) 对应像素 (100, 100)，指针放大为 8x8 并向左上偏移 2 像素
+	for _, c := range []struct {
+		x, y int
+		want color.RGBA
+	}{
+		{98, 98, red},
+		{105, 105, red},
+		{97, 98, blue},
+		{106, 105, blue},
+	} {
+		if got := img.RGBAAt(c.x, c.y); got != c.want {
+			t.Errorf("pixel (%d,%d) = %v, want %v", c.x, c.y, got, c.want)
+		}
+	}
+
+	// 指针在显示器外或隐藏时不绘制
+	for _, c := range []*fakeBackend{
+		{monitors: []Monitor{m}, cursorPos: image.Pt(10, 10), cursor: cursor},
+		{monitors: []Monitor{m}, cursorPos: image.Pt(150, 50), cursor: &Cursor{}},
+	} {
+		useFakeBackend(t, c)
+		img, err := CaptureMonitorWithOptions(m, CaptureOptions{IncludeCursor: true})
+		if err != nil {
+			t.Fatalf("CaptureMonitorWithOptions failed: %v", err)
+		}
+		if got := img.RGBAAt(100, 100); got != blue {
+			t.Errorf("cursor drawn at %v (visible %v)", c.cursorPos, c.cursor.Visible)
+		}
+	}
+}
+
+func TestCaptureDesktopIncludeCursor(t *testing.T) {
+	white := color.RGBA{255, 255, 255, 255}
+	black := color.RGBA{A: 255}
+
+	// 指针为 2x 图像，在 1x 桌面上缩小为一半
+	left := &fakeMonitor{width: 50, height: 50, img: solidImage(50, 50, white)}
+	right := &fakeMonitor{x: 50, width: 50, height: 50, img: solidImage(50, 50, white)}
+	cursor := &Cursor{Image: solidImage(8, 8, black), Scale: 2, Visible: true}
+	useFakeBackend(t, &fakeBackend{monitors: []Monitor{left, right}, cursorPos: image.Pt(70, 10), cursor: cursor})
+
+	img, err := CaptureDesktopWithOptions(CaptureOptions{IncludeCursor: true})
+	if err != nil {
+		t.Fatalf("CaptureDesktopWithOptions failed: %v", err)
+	}
+	if got := img.RGBAAt(73, 13); got != black {
+		t.Errorf("pixel (73,13) = %v, want cursor", got)
+	}
+	if got := img.RGBAAt(74, 14); got != white {
+		t.Errorf("pixel (74,14) = %v, want background", got)
+	}
+}
+
+func TestIncludeCursorKeepsBlankCheck(t *testing.T) {
+	m := &fakeMonitor{width: 10, height: 10, img: solidImage(10, 10, color.RGBA{A: 255})}
+	cursor := &Cursor{Image: solidImage(4, 4, color.RGBA{255, 255, 255, 255}), Scale: 1, Visible: true}
+	useFakeBackend(t, &fakeBackend{monitors: []Monitor{m}, cursorPos: image.Pt(5, 5), cursor: cursor})
+
+	_, err := CaptureMonitorWithOptions(m, CaptureOptions{IncludeCursor: true, RejectBlankFrames: true})
+	if !errors.Is(err, ErrBlankFrame) {
+		t.Errorf("expected ErrBlankFrame, got %v", err)
+	}
+}
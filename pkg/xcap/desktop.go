@@ -0,0 +1,96 @@
+package xcap
+
+import (
+	"image"
+	"image/draw"
+	"math"
+)
+
+// DesktopBounds 返回所有显示器组成的虚拟桌面在全局坐标系中的范围
+func DesktopBounds() (image.Rectangle, error) {
+	monitors, err := AllMonitors()
+	if err != nil {
+		return image.Rectangle{}, err
+	}
+	if len(monitors) == 0 {
+		return image.Rectangle{}, ErrNoMonitor
+	}
+
+	var b image.Rectangle
+	for _, m := range monitors {
+		b = b.Union(monitorRect(m))
+	}
+	return b, nil
+}
+
+// CaptureDesktop 截取所有显示器并按全局坐标拼接为一张图像
+//
+// 返回图像的 Bounds() 从 (0, 0) 开始，对应 DesktopBounds() 的左上角。
+// 显示器缩放比例不同时（如 Retina 屏与普通屏混用），以最大的比例为准，
+// 其余显示器的截图会按最近邻放大。显示器之间的空隙为全透明。
+func CaptureDesktop() (*image.RGBA, error) {
+	monitors, err := AllMonitors()
+	if err != nil {
+		return nil, err
+	}
+
+	img, _, err := captureDesktop(monitors)
+	return img, err
+}
+
+// captureDesktop 截取并拼接 monitors，同时返回图像覆盖的全局坐标范围
+func captureDesktop(monitors []Monitor) (*image.RGBA, image.Rectangle, error) {
+	if len(monitors) == 0 {
+		return nil, image.Rectangle{}, ErrNoMonitor
+	}
+
+	var bounds image.Rectangle
+	captures := make([]*image.RGBA, len(monitors))
+	scale := 1.0
+
+	for i, m := range monitors {
+		img, err := m.CaptureImage()
+		if err != nil {
+			return nil, image.Rectangle{}, err
+		}
+		captures[i] = img
+
+		r := monitorRect(m)
+		bounds = bounds.Union(r)
+		if r.Dx() > 0 {
+			scale = math.Max(scale, float64(img.Bounds().Dx())/float64(r.Dx()))
+		}
+	}
+
+	desktop := image.NewRGBA(image.Rect(0, 0,
+		int(math.Ceil(float64(bounds.Dx())*scale)),
+		int(math.Ceil(float64(bounds.Dy())*scale)),
+	))
+
+	for i, m := range monitors {
+		drawScaled(desktop, toPixelRect(monitorRect(m), bounds, desktop), captures[i])
+	}
+
+	return desktop, bounds, nil
+}
+
+// drawScaled 将 src 按最近邻缩放绘制到 dst 的区域 r
+func drawScaled(dst *image.RGBA, r image.Rectangle, src *image.RGBA) {
+	sb := src.Bounds()
+	if r.Dx() == sb.Dx() && r.Dy() == sb.Dy() {
+		draw.Draw(dst, r, src, sb.Min, draw.Src)
+		return
+	}
+	if r.Empty() || sb.Empty() {
+		return
+	}
+
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		sy := sb.Min.Y + (y-r.Min.Y)*sb.Dy()/r.Dy()
+		row := dst.Pix[dst.PixOffset(r.Min.X, y):]
+		for x := 0; x < r.Dx(); x++ {
+			sx := sb.Min.X + x*sb.Dx()/r.Dx()
+			copy(row[x*4:x*4+4], src.Pix[src.PixOffset(sx, sy):])
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package xcap
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestCaptureDesktopStitching(t *testing.T) {
+	// 左侧普通屏 100x50，右侧 Retina 屏几何 50x50（截图 100x100），顶部对齐
+	left := image.NewRGBA(image.Rect(0, 0, 100, 50))
+	fillRect(left, left.Bounds(), color.RGBA{255, 0, 0, 255})
+	right := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	fillRect(right, right.Bounds(), color.RGBA{0, 0, 255, 255})
+
+	monitors := []Monitor{
+		&fakeMonitor{x: -100, y: 0, width: 100, height: 50, scaleFactor: 1, img: left},
+		&fakeMonitor{x: 0, y: 0, width: 50, height: 50, scaleFactor: 2, img: right},
+	}
+
+	img, area, err := captureDesktop(monitors)
+	if err != nil {
+		t.Fatalf("captureDesktop failed: %v", err)
+	}
+	if want := image.Rect(-100, 0, 50, 50); area != want {
+		t.Errorf("area = %v, want %v", area, want)
+	}
+	if want := image.Rect(0, 0, 300, 100); img.Bounds() != want {
+		t.Fatalf("bounds = %v, want %v", img.Bounds(), want)
+	}
+
+	if got := img.RGBAAt(199, 99); got != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("left monitor pixel = %v, want red", got)
+	}
+	if got := img.RGBAAt(200, 0); got != (color.RGBA{0, 0, 255, 255}) {
+		t.Errorf("right monitor pixel = %v, want blue", got)
+	}
+
+	if _, _, err := captureDesktop(nil); err != ErrNoMonitor {
+		t.Errorf("no monitors: err = %v, want ErrNoMonitor", err)
+	}
+}
@@ -0,0 +1,153 @@
+package xcap
+
+import "image"
+
+// DefaultTileSize 是 Differ 默认的分块边长（像素）
+const DefaultTileSize = 32
+
+// DirtyRegion 表示一帧中发生变化的区域及其像素
+type DirtyRegion struct {
+	// Rect 变化区域在帧内的坐标
+	Rect image.Rectangle
+
+	// Image 变化区域的像素副本，Bounds() 与 Rect 相同
+	Image *image.RGBA
+}
+
+// Differ 按块比较相邻两帧，找出发生变化的矩形区域
+//
+// 桌面画面的相邻两帧通常只有少量区域不同，只传输或保存这些区域
+// 可以显著降低远程查看、录制和变化告警的开销。
+type Differ struct {
+	// TileSize 分块边长，<= 0 时使用 DefaultTileSize
+	TileSize int
+
+	// Tolerance 每个通道允许的最大差值，超过该值的像素视为发生变化
+	Tolerance uint8
+
+	prev *image.RGBA
+}
+
+// NewDiffer 创建一个新的 Differ
+func NewDiffer(tileSize int, tolerance uint8) *Differ {
+	return &Differ{TileSize: tileSize, Tolerance: tolerance}
+}
+
+// Diff 比较两帧，返回合并后的变化区域（坐标相对 cur 的 Bounds）
+// 两帧尺寸不同时整个 cur 视为发生变化
+func (d *Differ) Diff(prev, cur *image.RGBA) []image.Rectangle {
+	cb := cur.Bounds()
+	if prev == nil || prev.Bounds().Size() != cb.Size() {
+		if cb.Empty() {
+			return nil
+		}
+		return []image.Rectangle{cb}
+	}
+
+	tile := d.TileSize
+	if tile <= 0 {
+		tile = DefaultTileSize
+	}
+
+	cols := (cb.Dx() + tile - 1) / tile
+	rows := (cb.Dy() + tile - 1) / tile
+	dirty := make([]bool, cols*rows)
+
+	for ty := 0; ty < rows; ty++ {
+		for tx := 0; tx < cols; tx++ {
+			r := image.Rect(tx*tile, ty*tile, (tx+1)*tile, (ty+1)*tile).
+				Intersect(image.Rect(0, 0, cb.Dx(), cb.Dy()))
+			dirty[ty*cols+tx] = d.tileChanged(prev, cur, r)
+		}
+	}
+
+	return mergeTiles(dirty, cols, rows, tile, cb)
+}
+
+// Next 将 frame 与上一次传入的帧比较，只返回发生变化的区域
+// 第一帧（或尺寸变化后的第一帧）返回整帧；画面无变化时返回 nil
+// 适用于连续截图的流式场景，frame 在调用后可以被调用方复用
+func (d *Differ) Next(frame *image.RGBA) []DirtyRegion {
+	rects := d.Diff(d.prev, frame)
+
+	regions := make([]DirtyRegion, 0, len(rects))
+	for _, r := range rects {
+		regions = append(regions, DirtyRegion{Rect: r, Image: cropRGBA(frame, r)})
+	}
+
+	// 保存副本，避免调用方复用缓冲区导致比较失真
+	d.prev = cropRGBA(frame, frame.Bounds())
+
+	if len(regions) == 0 {
+		return nil
+	}
+	return regions
+}
+
+// Reset 清除保存的上一帧，下一次 Next 将返回整帧
+func (d *Differ) Reset() {
+	d.prev = nil
+}
+
+// tileChanged 判断分块 r（相对坐标）内是否有像素超出容差
+func (d *Differ) tileChanged(prev, cur *image.RGBA, r image.Rectangle) bool {
+	pb, cb := prev.Bounds(), cur.Bounds()
+	tol := int(d.Tolerance)
+
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		p := prev.Pix[prev.PixOffset(pb.Min.X+r.Min.X, pb.Min.Y+y):prev.PixOffset(pb.Min.X+r.Max.X, pb.Min.Y+y)]
+		c := cur.Pix[cur.PixOffset(cb.Min.X+r.Min.X, cb.Min.Y+y):cur.PixOffset(cb.Min.X+r.Max.X, cb.Min.Y+y)]
+		for i := range c {
+			diff := int(c[i]) - int(p[i])
+			if diff > tol || -diff > tol {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mergeTiles 将变化的分块合并为尽量少的矩形
+// 先把每行中连续的分块合并为横条，再把上下相邻且横向范围相同的横条合并
+func mergeTiles(dirty []bool, cols, rows, tile int, bounds image.Rectangle) []image.Rectangle {
+	type span struct{ x0, x1, y0, y1 int }
+
+	var done []span
+	var open []span
+
+	for ty := 0; ty < rows; ty++ {
+		var next []span
+		for tx := 0; tx < cols; {
+			if !dirty[ty*cols+tx] {
+				tx++
+				continue
+			}
+			start := tx
+			for tx < cols && dirty[ty*cols+tx] {
+				tx++
+			}
+
+			s := span{start, tx, ty, ty + 1}
+			for i, o := range open {
+				if o.x0 == s.x0 && o.x1 == s.x1 {
+					s.y0 = o.y0
+					open = append(open[:i], open[i+1:]...)
+					break
+				}
+			}
+			next = append(next, s)
+		}
+		done = append(done, open...)
+		open = next
+	}
+	done = append(done, open...)
+
+	rects := make([]image.Rectangle, 0, len(done))
+	for _, s := range done {
+		r := image.Rect(s.x0*tile, s.y0*tile, s.x1*tile, s.y1*tile).
+			Add(bounds.Min).
+			Intersect(bounds)
+		rects = append(rects, r)
+	}
+	return rects
+}
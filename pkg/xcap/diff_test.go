@@ -0,0 +1,80 @@
+package xcap
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestDifferIdenticalFrames(t *testing.T) {
+	a := image.NewRGBA(image.Rect(0, 0, 100, 80))
+	b := image.NewRGBA(image.Rect(0, 0, 100, 80))
+
+	d := NewDiffer(16, 0)
+	if rects := d.Diff(a, b); len(rects) != 0 {
+		t.Fatalf("Diff of identical frames = %v, want none", rects)
+	}
+}
+
+func TestDifferMergesTiles(t *testing.T) {
+	a := image.NewRGBA(image.Rect(0, 0, 100, 80))
+	b := image.NewRGBA(image.Rect(0, 0, 100, 80))
+
+	// 跨越 2x2 个分块的变化应合并为一个矩形
+	fillRect(b, image.Rect(10, 10, 40, 30), color.RGBA{255, 255, 255, 255})
+	// 最右下角的单个像素，分块需裁剪到图像边界
+	b.SetRGBA(99, 79, color.RGBA{255, 0, 0, 255})
+
+	d := NewDiffer(16, 0)
+	rects := d.Diff(a, b)
+
+	want := []image.Rectangle{
+		image.Rect(0, 0, 48, 32),
+		image.Rect(96, 64, 100, 80),
+	}
+	if len(rects) != len(want) {
+		t.Fatalf("Diff = %v, want %v", rects, want)
+	}
+	for i := range want {
+		if rects[i] != want[i] {
+			t.Errorf("rect %d = %v, want %v", i, rects[i], want[i])
+		}
+	}
+}
+
+func TestDifferTolerance(t *testing.T) {
+	a := image.NewRGBA(image.Rect(0, 0, 32, 32))
+	b := image.NewRGBA(image.Rect(0, 0, 32, 32))
+	fillRect(b, b.Bounds(), color.RGBA{3, 3, 3, 0})
+
+	if rects := NewDiffer(8, 3).Diff(a, b); len(rects) != 0 {
+		t.Errorf("Diff within tolerance = %v, want none", rects)
+	}
+	if rects := NewDiffer(8, 2).Diff(a, b); len(rects) != 1 || rects[0] != b.Bounds() {
+		t.Errorf("Diff above tolerance = %v, want whole frame", rects)
+	}
+}
+
+func TestDifferNext(t *testing.T) {
+	d := NewDiffer(10, 0)
+	frame := image.NewRGBA(image.Rect(0, 0, 40, 40))
+
+	regions := d.Next(frame)
+	if len(regions) != 1 || regions[0].Rect != frame.Bounds() {
+		t.Fatalf("first Next = %v, want whole frame", regions)
+	}
+
+	if regions := d.Next(frame); regions != nil {
+		t.Fatalf("unchanged Next = %v, want nil", regions)
+	}
+
+	// 复用同一个缓冲区修改画面，Differ 必须保存了自己的副本
+	frame.SetRGBA(25, 5, color.RGBA{0, 255, 0, 255})
+	regions = d.Next(frame)
+	if len(regions) != 1 || regions[0].Rect != image.Rect(20, 0, 30, 10) {
+		t.Fatalf("changed Next = %v, want [(20,0)-(30,10)]", regions)
+	}
+	if got := regions[0].Image.RGBAAt(25, 5); got != (color.RGBA{0, 255, 0, 255}) {
+		t.Errorf("region pixel = %v, want green", got)
+	}
+}
@@ -33,4 +33,17 @@
 //	    }
 //	    // 使用 img...
 //	}
+//
+// 并发：
+//
+// 包级函数以及 Monitor、Window 的所有方法都可以被多个 goroutine 同时调用。
+// 平台后端把原生调用（枚举、截图、窗口状态查询）放到同一个锁定的 OS 线程上
+// 依次执行，因此并发调用是安全的，但不会并行加速；ID、坐标等属性
+// 在枚举时缓存，读取时不经过该线程。某个原生调用卡住时，后续调用都会等待；
+// 需要超时的场景请使用 CaptureImageContext、AllMonitorsContext 和 AllWindowsContext。
+// 这些调用超时后只是不再等待，卡住的原生调用仍在该线程上运行完毕，
+// 之后的调用继续排在它后面，带 Context 的调用在排队超时后返回。
+//
+// Differ、Redactor、Compositor 等带状态的类型不是并发安全的，
+// 同一个值不能在多个 goroutine 中同时使用。
 package xcap
@@ -0,0 +1,228 @@
+package xcap
+
+import (
+	"errors"
+	"strings"
+)
+
+// EDID 块大小和描述符布局（VESA E-EDID 1.3/1.4）
+const (
+	edidBlockSize      = 128
+	edidDescriptorBase = 54
+	edidDescriptorSize = 18
+)
+
+var edidHeader = [8]byte{0x00, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x00}
+
+// ErrInvalidEDID 在 EDID 数据长度、头部或校验和不正确时由 ParseEDID 返回
+var ErrInvalidEDID = errors.New("xcap: invalid EDID")
+
+// EDID 是从显示器 EDID 基本块解析出的信息
+type EDID struct {
+	// ManufacturerID 三个字母的 PNP 厂商 ID，如 "DEL"
+	ManufacturerID string
+
+	// ProductCode 厂商定义的产品代码
+	ProductCode uint16
+
+	// SerialNumber 基本块中的数字序列号，未提供时为 0
+	SerialNumber uint32
+
+	// SerialString 显示器序列号描述符（0xFF）中的文本
+	SerialString string
+
+	// Name 显示器名称描述符（0xFC）中的文本，如 "DELL U2720Q"
+	Name string
+
+	// Week、Year 生产周和年份，Week 为 0 表示未提供；ModelYear 为 true 时 Year 是型号年份
+	Week      int
+	Year      int
+	ModelYear bool
+
+	// Version、Revision EDID 结构版本，如 1.4
+	Version  int
+	Revision int
+
+	// Digital 是否为数字输入
+	Digital bool
+
+	// BitsPerColor 每个颜色通道的位数，仅 EDID 1.4 数字输入提供，未知时为 0
+	BitsPerColor int
+
+	// Interface EDID 1.4 数字输入声明的接口类型，未声明时为 ConnectorUnknown
+	Interface Connector
+
+	// WidthMM、HeightMM 显示区域的物理尺寸（毫米），未知时为 0
+	WidthMM  int
+	HeightMM int
+
+	// PreferredWidth、PreferredHeight、PreferredRefresh 首选分辨率和刷新率（第一个详细时序描述符）
+	PreferredWidth   int
+	PreferredHeight  int
+	PreferredRefresh float64
+
+	// Extensions 后续扩展块（如 CEA-861）的数量
+	Extensions int
+}
+
+// ParseEDID 解析 EDID 的 128 字节基本块，扩展块被忽略
+// 数据不足 128 字节、头部不匹配或校验和错误时返回 ErrInvalidEDID
+func ParseEDID(data []byte) (*EDID, error) {
+	if len(data) < edidBlockSize || [8]byte(data[:8]) != edidHeader {
+		return nil, ErrInvalidEDID
+	}
+
+	var sum byte
+	for _, b := range data[:edidBlockSize] {
+		sum += b
+	}
+	if sum != 0 {
+		return nil, ErrInvalidEDID
+	}
+
+	e := &EDID{
+		ManufacturerID: pnpID(uint16(data[8])<<8 | uint16(data[9])),
+		ProductCode:    uint16(data[10]) | uint16(data[11])<<8,
+		SerialNumber:   uint32(data[12]) | uint32(data[13])<<8 | uint32(data[14])<<16 | uint32(data[15])<<24,
+		Version:        int(data[18]),
+		Revision:       int(data[19]),
+		Digital:        data[20]&0x80 != 0,
+		Extensions:     int(data[126]),
+	}
+
+	// 第 16 字节为 0xFF 时第 17 字节是型号年份
+	if data[16] == 0xff {
+		e.ModelYear = true
+	} else {
+		e.Week = int(data[16])
+	}
+	if data[17] != 0 {
+		e.Year = 1990 + int(data[17])
+	}
+
+	if e.Digital && (e.Version > 1 || e.Revision >= 4) {
+		e.BitsPerColor = edidBitDepths[(data[20]>>4)&0x07]
+		e.Interface = edidInterfaces[data[20]&0x0f]
+	}
+
+	// 第 21、22 字节为厘米尺寸；其中一个为 0 时表示宽高比而不是尺寸
+	if data[21] != 0 && data[22] != 0 {
+		e.WidthMM, e.HeightMM = int(data[21])*10, int(data[22])*10
+	}
+
+	for i := 0; i < 4; i++ {
+		off := edidDescriptorBase + i*edidDescriptorSize
+		e.parseDescriptor(data[off:off+edidDescriptorSize], i == 0)
+	}
+
+	return e, nil
+}
+
+// parseDescriptor 解析一个 18 字节描述符；first 为 true 时是首选时序所在的位置
+func (e *EDID) parseDescriptor(d []byte, first bool) {
+	if d[0] != 0 || d[1] != 0 {
+		if first {
+			e.parseTiming(d)
+		}
+		return
+	}
+
+	switch d[3] {
+	case 0xff:
+		e.SerialString = descriptorText(d[5:])
+	case 0xfc:
+		e.Name = descriptorText(d[5:])
+	}
+}
+
+// parseTiming 从详细时序描述符中读取首选分辨率、刷新率和毫米级的物理尺寸
+func (e *EDID) parseTiming(d []byte) {
+	clock := float64(uint16(d[0])|uint16(d[1])<<8) * 10000
+	hActive := int(d[2]) | int(d[4]>>4)<<8
+	hBlank := int(d[3]) | int(d[4]&0x0f)<<8
+	vActive := int(d[5]) | int(d[7]>>4)<<8
+	vBlank := int(d[6]) | int(d[7]&0x0f)<<8
+
+	e.PreferredWidth, e.PreferredHeight = hActive, vActive
+	if total := (hActive + hBlank) * (vActive + vBlank); total > 0 {
+		e.PreferredRefresh = clock / float64(total)
+	}
+
+	// 时序描述符中的尺寸精确到毫米，优先于基本块中的厘米值
+	w := int(d[12]) | int(d[14]>>4)<<8
+	h := int(d[13]) | int(d[14]&0x0f)<<8
+	if w > 0 && h > 0 {
+		e.WidthMM, e.HeightMM = w, h
+	}
+}
+
+// descriptorText 返回描述符中的 ASCII 文本，文本以 0x0A 结束并以空格填充
+func descriptorText(b []byte) string {
+	if i := strings.IndexByte(string(b), '\n'); i >= 0 {
+		b = b[:i]
+	}
+	return strings.TrimSpace(strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e {
+			return -1
+		}
+		return r
+	}, string(b)))
+}
+
+// pnpID 把 EDID（以及 macOS CGDisplayVendorNumber）中的 16 位厂商编码转换为三个字母
+// 每个字母占 5 位，1 表示 'A'
+func pnpID(code uint16) string {
+	if code == 0 {
+		return ""
+	}
+	b := []byte{
+		byte(code>>10&0x1f) + 'A' - 1,
+		byte(code>>5&0x1f) + 'A' - 1,
+		byte(code&0x1f) + 'A' - 1,
+	}
+	for _, c := range b {
+		if c < 'A' || c > 'Z' {
+			return ""
+		}
+	}
+	return string(b)
+}
+
+// edidBitDepths 为 EDID 1.4 视频输入字节 bit 6-4 对应的每通道位数
+var edidBitDepths = [8]int{0, 6, 8, 10, 12, 14, 16, 0}
+
+// edidInterfaces 为 EDID 1.4 视频输入字节 bit 3-0 对应的接口类型
+var edidInterfaces = [16]Connector{
+	1: ConnectorDVI,
+	2: ConnectorHDMI,
+	3: ConnectorHDMI,
+	5: ConnectorDisplayPort,
+}
+
+// pnpVendors 常见显示器和面板厂商的 PNP ID
+var pnpVendors = map[string]string{
+	"ACR": "Acer",
+	"AOC": "AOC",
+	"APP": "Apple",
+	"AUO": "AU Optronics",
+	"AUS": "ASUS",
+	"BNQ": "BenQ",
+	"BOE": "BOE",
+	"CMN": "Innolux",
+	"DEL": "Dell",
+	"ENC": "EIZO",
+	"GSM": "LG Electronics",
+	"HPN": "HP",
+	"HWP": "HP",
+	"IVM": "iiyama",
+	"LEN": "Lenovo",
+	"LGD": "LG Display",
+	"MSI": "MSI",
+	"NEC": "NEC",
+	"PHL": "Philips",
+	"SAM": "Samsung",
+	"SDC": "Samsung Display",
+	"SHP": "Sharp",
+	"SNY": "Sony",
+	"VSC": "ViewSonic",
+}
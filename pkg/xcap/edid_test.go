@@ -0,0 +1,364 @@
+package xcap
+
+import (
+	"encoding/hex"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+// edidBuilder 按 E-EDID 布局构造测试用的基本块，字段取值参照真实显示器
+type edidBuilder struct {
+	vendor       string
+	product      uint16
+	serial       uint32
+	week, year   byte
+	version      byte
+	revision     byte
+	input        byte
+	widthCM      byte
+	heightCM     byte
+	timing       []byte // 首选详细时序描述符，nil 表示不提供
+	descriptors  map[byte]string
+	extensionCnt byte
+}
+
+func (b edidBuilder) build() []byte {
+	d := make([]byte, edidBlockSize)
+	copy(d, edidHeader[:])
+
+	code := uint16(b.vendor[0]-'A'+1)<<10 | uint16(b.vendor[1]-'A'+1)<<5 | uint16(b.vendor[2]-'A'+1)
+	d[8], d[9] = byte(code>>8), byte(code)
+	d[10], d[11] = byte(b.product), byte(b.product>>8)
+	d[12], d[13], d[14], d[15] = byte(b.serial), byte(b.serial>>8), byte(b.serial>>16), byte(b.serial>>24)
+	d[16], d[17] = b.week, b.year
+	d[18], d[19] = b.version, b.revision
+	d[20], d[21], d[22] = b.input, b.widthCM, b.heightCM
+
+	slot := 0
+	if b.timing != nil {
+		copy(d[edidDescriptorBase:], b.timing)
+		slot++
+	}
+	for _, tag := range []byte{0xff, 0xfc, 0xfe} {
+		text, ok := b.descriptors[tag]
+		if !ok || slot >= 4 {
+			continue
+		}
+		off := edidDescriptorBase + slot*edidDescriptorSize
+		d[off+3] = tag
+		body := d[off+5 : off+edidDescriptorSize]
+		for i := range body {
+			body[i] = ' '
+		}
+		n := copy(body, text)
+		if n < len(body) {
+			body[n] = '\n'
+		}
+		slot++
+	}
+
+	d[126] = b.extensionCnt
+	var sum byte
+	for _, v := range d[:127] {
+		sum += v
+	}
+	d[127] = -sum
+	return d
+}
+
+// timingDescriptor 构造详细时序描述符，clock 单位为 10 kHz
+func timingDescriptor(clock uint16, hActive, hBlank, vActive, vBlank, widthMM, heightMM int) []byte {
+	d := make([]byte, edidDescriptorSize)
+	d[0], d[1] = byte(clock), byte(clock>>8)
+	d[2], d[3], d[4] = byte(hActive), byte(hBlank), byte(hActive>>8<<4|hBlank>>8)
+	d[5], d[6], d[7] = byte(vActive), byte(vBlank), byte(vActive>>8<<4|vBlank>>8)
+	d[12], d[13], d[14] = byte(widthMM), byte(heightMM), byte(widthMM>>8<<4|heightMM>>8)
+	return d
+}
+
+// 外接显示器：EDID 1.4、DisplayPort、10 位色深，1920x1200@60，带名称和序列号
+var dellEDID = edidBuilder{
+	vendor: "DEL", product: 0xa0ba, serial: 0x4c4d3130,
+	week: 12, year: 25, version: 1, revision: 4,
+	input: 0x80 | 0x30 | 0x05, widthCM: 52, heightCM: 32,
+	timing: timingDescriptor(15400, 1920, 160, 1200, 35, 518, 324),
+	descriptors: map[byte]string{
+		0xff: "7MT0154S0ABL",
+		0xfc: "DELL U2415",
+	},
+	extensionCnt: 1,
+}.build()
+
+// 笔记本面板：EDID 1.3，没有名称描述符，只有厂商文本
+var panelEDID = edidBuilder{
+	vendor: "BOE", product: 0x0747, serial: 0,
+	week: 0xff, year: 29, version: 1, revision: 3,
+	input: 0x80, widthCM: 31, heightCM: 17,
+	timing: timingDescriptor(14100, 1920, 160, 1080, 40, 309, 174),
+	descriptors: map[byte]string{
+		0xfe: "NV140FHM-N62",
+	},
+}.build()
+
+func TestParseEDID(t *testing.T) {
+	e, err := ParseEDID(dellEDID)
+	if err != nil {
+		t.Fatalf("ParseEDID failed: %v", err)
+	}
+
+	if e.ManufacturerID != "DEL" || e.ProductCode != 0xa0ba || e.SerialNumber != 0x4c4d3130 {
+		t.Errorf("identity = %q %#x %#x", e.ManufacturerID, e.ProductCode, e.SerialNumber)
+	}
+	if e.Name != "DELL U2415" || e.SerialString != "7MT0154S0ABL" {
+		t.Errorf("descriptors = %q %q", e.Name, e.SerialString)
+	}
+	if e.Week != 12 || e.Year != 2015 || e.ModelYear {
+		t.Errorf("date = week %d year %d model %v", e.Week, e.Year, e.ModelYear)
+	}
+	if e.Version != 1 || e.Revision != 4 || !e.Digital {
+		t.Errorf("version = %d.%d digital %v", e.Version, e.Revision, e.Digital)
+	}
+	if e.BitsPerColor != 10 || e.Interface != ConnectorDisplayPort {
+		t.Errorf("input = %d bits, %v", e.BitsPerColor, e.Interface)
+	}
+	// 时序描述符中的毫米尺寸优先于厘米值
+	if e.WidthMM != 518 || e.HeightMM != 324 {
+		t.Errorf("size = %dx%d mm", e.WidthMM, e.HeightMM)
+	}
+	if e.PreferredWidth != 1920 || e.PreferredHeight != 1200 || math.Abs(e.PreferredRefresh-59.95) > 0.01 {
+		t.Errorf("preferred = %dx%d@%.2f", e.PreferredWidth, e.PreferredHeight, e.PreferredRefresh)
+	}
+	if e.Extensions != 1 {
+		t.Errorf("extensions = %d", e.Extensions)
+	}
+}
+
+func TestParseEDIDv13(t *testing.T) {
+	e, err := ParseEDID(panelEDID)
+	if err != nil {
+		t.Fatalf("ParseEDID failed: %v", err)
+	}
+
+	if e.ManufacturerID != "BOE" || e.Name != "" || e.SerialString != "" {
+		t.Errorf("identity = %q %q %q", e.ManufacturerID, e.Name, e.SerialString)
+	}
+	if !e.ModelYear || e.Week != 0 || e.Year != 2019 {
+		t.Errorf("date = week %d year %d model %v", e.Week, e.Year, e.ModelYear)
+	}
+	// EDID 1.3 不声明色深和接口
+	if e.BitsPerColor != 0 || e.Interface != ConnectorUnknown {
+		t.Errorf("input = %d bits, %v", e.BitsPerColor, e.Interface)
+	}
+	if e.WidthMM != 309 || e.HeightMM != 174 || e.PreferredHeight != 1080 {
+		t.Errorf("size = %dx%d mm, preferred height %d", e.WidthMM, e.HeightMM, e.PreferredHeight)
+	}
+}
+
+// 完整的 EDID 数据（与 sysfs edid 文件相同的字节），按 VESA E-EDID 和 CEA-861 的布局逐字节写出，
+// 不经过 edidBuilder，覆盖描述符顺序、额外的详细时序、厂商文本和扩展块等显示器常见的布局。
+// 序列号等标识是虚构的；换成从硬件读出的转储时只需更新期望值
+
+// DisplayPort 外接显示器：EDID 1.4，序列号描述符在名称之前，最后是范围限制描述符
+var dpMonitorDump = hexBytes(`
+	00 ff ff ff ff ff ff 00 10 ac 24 42 30 30 42 4c
+	1e 1e 01 04 b5 3c 22 78 3a ee 91 a3 54 4c 99 26
+	0f 50 54 a5 4b 00 d1 c0 b3 00 a9 c0 81 80 81 00
+	71 4f 01 01 01 01 4d d0 00 a0 f0 70 3e 80 30 20
+	35 00 55 50 21 00 00 1a 00 00 00 ff 00 43 46 56
+	39 4e 38 41 42 30 44 32 4c 0a 00 00 00 fc 00 44
+	45 4c 4c 20 55 32 37 32 30 51 0a 20 00 00 00 fd
+	00 18 4b 1e 8c 3c 00 0a 20 20 20 20 20 20 00 95
+`)
+
+// 笔记本 eDP 面板：EDID 1.4、6 位色深，两个详细时序（第二个是低刷新率模式），两个厂商文本描述符
+var laptopPanelDump = hexBytes(`
+	00 ff ff ff ff ff ff 00 09 e5 47 07 00 00 00 00
+	00 1d 01 04 95 1f 11 78 0a 07 a6 a4 57 4d 9d 26
+	10 4f 53 00 00 00 01 01 01 01 01 01 01 01 01 01
+	01 01 01 01 01 01 9a 36 80 a0 70 38 28 40 30 20
+	35 00 35 ae 10 00 00 18 ae 2b 80 a0 70 38 28 40
+	30 20 35 00 35 ae 10 00 00 18 00 00 00 fe 00 42
+	4f 45 20 48 46 0a 20 20 20 20 20 20 00 00 00 fe
+	00 4e 56 31 34 30 46 48 4d 2d 4e 36 32 0a 00 73
+`)
+
+// HDMI 电视：EDID 1.3 基本块加 CEA-861 扩展块（视频、音频、扬声器和 HDMI 厂商数据块）
+var ceaTVDump = hexBytes(`
+	00 ff ff ff ff ff ff 00 4c 2d 66 0f 01 00 00 00
+	01 1b 01 03 80 a0 5a 78 0a ee 91 a3 54 4c 99 26
+	0f 50 54 bd ef 80 71 4f 81 c0 81 00 81 80 95 00
+	a9 c0 b3 00 01 01 02 3a 80 18 71 38 2d 40 58 2c
+	45 00 40 84 63 00 00 1e 01 1d 00 72 51 d0 1e 20
+	6e 28 55 00 40 84 63 00 00 1e 00 00 00 fd 00 18
+	4b 1a 51 17 00 0a 20 20 20 20 20 20 00 00 00 fc
+	00 53 41 4d 53 55 4e 47 0a 20 20 20 20 20 01 dd
+	02 03 20 f1 4a 90 1f 04 13 05 14 20 22 03 12 23
+	09 07 07 83 01 00 00 68 03 0c 00 10 00 80 1e 00
+	02 3a 80 d0 72 38 2d 40 10 2c 45 80 40 84 63 00
+	00 1e 8c 0a d0 8a 20 e0 2d 10 10 3e 96 00 40 84
+	63 00 00 18 00 00 00 00 00 00 00 00 00 00 00 00
+	00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00
+	00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00
+	00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 4e
+`)
+
+// hexBytes 解析以空白分隔的十六进制字节
+func hexBytes(s string) []byte {
+	b, err := hex.DecodeString(strings.Join(strings.Fields(s), ""))
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestParseEDIDDumps(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		data    []byte
+		refresh float64
+		want    EDID
+	}{
+		{"displayport monitor", dpMonitorDump, 59.997, EDID{
+			ManufacturerID: "DEL", ProductCode: 0x4224, SerialNumber: 0x4c423030,
+			SerialString: "CFV9N8AB0D2L", Name: "DELL U2720Q",
+			Week: 30, Year: 2020, Version: 1, Revision: 4,
+			Digital: true, BitsPerColor: 10, Interface: ConnectorDisplayPort,
+			WidthMM: 597, HeightMM: 336, PreferredWidth: 3840, PreferredHeight: 2160,
+		}},
+		// 第二个详细时序（48 Hz）不是首选时序；厂商文本（0xFE）不作为名称
+		{"laptop panel", laptopPanelDump, 60.0, EDID{
+			ManufacturerID: "BOE", ProductCode: 0x0747,
+			Year: 2019, Version: 1, Revision: 4,
+			Digital: true, BitsPerColor: 6, Interface: ConnectorDisplayPort,
+			WidthMM: 309, HeightMM: 174, PreferredWidth: 1920, PreferredHeight: 1080,
+		}},
+		// EDID 1.3 不声明色深和接口，扩展块只计数
+		{"tv with CEA extension", ceaTVDump, 60.0, EDID{
+			ManufacturerID: "SAM", ProductCode: 0x0f66, SerialNumber: 1,
+			Name: "SAMSUNG", Week: 1, Year: 2017, Version: 1, Revision: 3,
+			Digital: true, WidthMM: 1600, HeightMM: 900,
+			PreferredWidth: 1920, PreferredHeight: 1080, Extensions: 1,
+		}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			e, err := ParseEDID(c.data)
+			if err != nil {
+				t.Fatalf("ParseEDID failed: %v", err)
+			}
+			if math.Abs(e.PreferredRefresh-c.refresh) > 0.005 {
+				t.Errorf("preferred refresh = %.3f, want %.3f", e.PreferredRefresh, c.refresh)
+			}
+			got := *e
+			got.PreferredRefresh = 0
+			if got != c.want {
+				t.Errorf("got  %+v\nwant %+v", got, c.want)
+			}
+		})
+	}
+
+	// 扩展块有自己的校验和，基本块的解析不受其影响
+	if len(ceaTVDump) != 2*edidBlockSize || ceaTVDump[edidBlockSize] != 0x02 {
+		t.Fatal("TV dump should carry a CEA-861 extension block")
+	}
+	bad := append([]byte(nil), ceaTVDump...)
+	bad[len(bad)-1]++
+	if _, err := ParseEDID(bad); err != nil {
+		t.Errorf("extension checksum affected base block parsing: %v", err)
+	}
+}
+
+func TestParseEDIDInvalid(t *testing.T) {
+	bad := append([]byte(nil), dellEDID...)
+	bad[100]++
+
+	tests := map[string][]byte{
+		"short":    dellEDID[:100],
+		"header":   append([]byte{1}, dellEDID[1:]...),
+		"checksum": bad,
+		"empty":    nil,
+	}
+	for name, data := range tests {
+		if _, err := ParseEDID(data); !errors.Is(err, ErrInvalidEDID) {
+			t.Errorf("%s: expected ErrInvalidEDID, got %v", name, err)
+		}
+	}
+}
+
+func TestNewMonitorInfo(t *testing.T) {
+	// EDID 字段优先，系统 API 的连接方式优先于 EDID 声明的接口
+	info := newMonitorInfo(nativeMonitorInfo{
+		name:      "DELL U2415 (1)",
+		edid:      dellEDID,
+		connector: ConnectorHDMI,
+	})
+	if info.Manufacturer != "DEL" || info.ManufacturerName != "Dell" || info.Model != "DELL U2415" {
+		t.Errorf("identity = %q %q %q", info.Manufacturer, info.ManufacturerName, info.Model)
+	}
+	if info.SerialNumber != "7MT0154S0ABL" || info.Year != 2015 {
+		t.Errorf("serial %q year %d", info.SerialNumber, info.Year)
+	}
+	if info.WidthMM != 518 || info.ColorDepth != 10 || info.Connector != ConnectorHDMI {
+		t.Errorf("width %d depth %d connector %v", info.WidthMM, info.ColorDepth, info.Connector)
+	}
+
+	// 没有 EDID 时使用系统 API 的值
+	info = newMonitorInfo(nativeMonitorInfo{
+		name:         "Built-in Retina Display",
+		vendor:       0x0610, // APP
+		product:      0xa050,
+		serial:       4251086178,
+		widthMM:      302,
+		heightMM:     196,
+		connector:    ConnectorInternal,
+		bitsPerColor: 8,
+	})
+	if info.Manufacturer != "APP" || info.ManufacturerName != "Apple" || info.Model != "Built-in Retina Display" {
+		t.Errorf("identity = %q %q %q", info.Manufacturer, info.ManufacturerName, info.Model)
+	}
+	if info.SerialNumber != "4251086178" || info.WidthMM != 302 || info.ColorDepth != 8 || info.EDID != nil {
+		t.Errorf("fallback = %+v", info)
+	}
+
+	// 无法解析的 EDID 保留原始数据，名称回退到系统名称
+	info = newMonitorInfo(nativeMonitorInfo{name: "Generic PnP Monitor", edid: []byte{1, 2, 3}})
+	if info.Model != "Generic PnP Monitor" || len(info.EDID) != 3 || info.Manufacturer != "" {
+		t.Errorf("invalid EDID = %+v", info)
+	}
+}
+
+func TestMonitorInfoFromFake(t *testing.T) {
+	m := &fakeMonitor{name: "Panel", edid: panelEDID}
+	info, err := m.Info()
+	if err != nil {
+		t.Fatalf("Info failed: %v", err)
+	}
+	if info.Manufacturer != "BOE" || info.Model != "Panel" || info.SerialNumber != "" {
+		t.Errorf("info = %+v", info)
+	}
+}
+
+func TestPnpID(t *testing.T) {
+	tests := map[uint16]string{
+		0x10ac: "DEL",
+		0x0610: "APP",
+		0x1e6d: "GSM",
+		0:      "",
+		0x7fff: "",
+	}
+	for code, want := range tests {
+		if got := pnpID(code); got != want {
+			t.Errorf("pnpID(%#x) = %q, want %q", code, got, want)
+		}
+	}
+}
+
+func TestConnectorString(t *testing.T) {
+	if ConnectorDisplayPort.String() != "displayport" || ConnectorInternal.String() != "internal" {
+		t.Error("unexpected connector names")
+	}
+	if Connector(99).String() != "unknown" {
+		t.Error("expected unknown for invalid connector")
+	}
+}
@@ -1,6 +1,12 @@
 package xcap
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/zn-chen/xcap/internal/errcode"
+)
 
 // 通用错误定义
 var (
@@ -17,12 +23,133 @@ var (
 	// 在 macOS 上，通常表示未授予 Screen Recording 权限
 	ErrPermissionDenied = errors.New("xcap: permission denied")
 
+	// ErrWindowClosed 在对枚举后已关闭的窗口进行操作时返回
+	// errors.Is(ErrWindowClosed, ErrNoWindow) 为 true，兼容按 ErrNoWindow 判断的代码
+	ErrWindowClosed error = windowClosedError{}
+
 	// ErrWindowMinimized 在尝试截取最小化窗口时返回
 	ErrWindowMinimized = errors.New("xcap: window is minimized")
 
 	// ErrInvalidRegion 在截图区域无效时返回
 	ErrInvalidRegion = errors.New("xcap: invalid capture region")
 
+	// ErrInvalidFilter 在 ResampleFilter 不是已定义的滤波器时返回
+	ErrInvalidFilter = errors.New("xcap: invalid resample filter")
+
+	// ErrBlankFrame 在启用 CaptureOptions.RejectBlankFrames 且截图为空白帧时返回
+	// 可以用 errors.As 取出 *BlankFrameError 查看帧质量
+	ErrBlankFrame = errors.New("xcap: blank frame")
+
 	// ErrNotSupported 在当前平台不支持该功能时返回
 	ErrNotSupported = errors.New("xcap: not supported on this platform")
 )
+
+// Error 描述一次失败的平台操作
+//
+// Error 同时包装对应的公开错误和后端返回的原始错误，
+// 因此既可以用 errors.Is(err, ErrCaptureFailed) 判断错误类别，
+// 也可以用 errors.As 取出 *Error 查看操作、目标和后端错误码。
+type Error struct {
+	// Op 失败的操作，如 "capture monitor"
+	Op string
+
+	// ID 目标显示器或窗口的 ID，没有具体目标（如枚举）时为 0
+	ID uint32
+
+	// Code 后端错误码（见 internal/*/bridge.h 的 XCAP_ERR_*），错误不来自 C 层时为 0
+	Code int
+
+	// Kind 对应的公开错误，如 ErrCaptureFailed
+	Kind error
+
+	// Err 后端返回的原始错误
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.ID != 0 {
+		return fmt.Sprintf("xcap: %s %d: %v", e.Op, e.ID, e.Err)
+	}
+	return fmt.Sprintf("xcap: %s: %v", e.Op, e.Err)
+}
+
+// Unwrap 返回公开错误和原始错误，供 errors.Is/errors.As 使用
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// 操作的目标类型，决定 NotFound 错误码映射为 ErrNoMonitor 还是 ErrNoWindow
+const (
+	targetMonitor = iota
+	targetWindow
+)
+
+// newError 将后端错误包装为 *Error，err 为 nil 时返回 nil
+func newError(op string, target int, id uint32, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var xe *Error
+	if errors.As(err, &xe) {
+		return err
+	}
+
+	// ctx 结束不是平台错误，原样返回
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	e := &Error{Op: op, ID: id, Kind: ErrCaptureFailed, Err: err}
+
+	var be *errcode.Error
+	switch {
+	case errors.As(err, &be):
+		e.Code = int(be.Code)
+		switch be.Code {
+		case errcode.NoMonitors:
+			e.Kind = ErrNoMonitor
+		case errcode.NoWindows:
+			e.Kind = ErrNoWindow
+		case errcode.PermissionDenied:
+			e.Kind = ErrPermissionDenied
+		case errcode.NotFound:
+			switch {
+			case target == targetWindow && id != 0:
+				e.Kind = ErrWindowClosed
+			case target == targetWindow:
+				e.Kind = ErrNoWindow
+			default:
+				e.Kind = ErrNoMonitor
+			}
+		}
+	case errors.Is(err, errcode.ErrNotSupported):
+		e.Kind = ErrNotSupported
+	case errors.Is(err, errcode.ErrInvalidRegion):
+		e.Kind = ErrInvalidRegion
+	}
+
+	return e
+}
+
+// monitorError 包装显示器操作的后端错误
+func monitorError(op string, id uint32, err error) error {
+	return newError(op, targetMonitor, id, err)
+}
+
+// windowError 包装窗口操作的后端错误
+func windowError(op string, id uint32, err error) error {
+	return newError(op, targetWindow, id, err)
+}
+
+// windowClosedError 是 ErrWindowClosed 的类型，同时匹配 ErrNoWindow
+type windowClosedError struct{}
+
+func (windowClosedError) Error() string {
+	return "xcap: window closed"
+}
+
+// Is 使 errors.Is(ErrWindowClosed, ErrNoWindow) 成立
+func (windowClosedError) Is(target error) bool {
+	return target == ErrNoWindow
+}
@@ -0,0 +1,100 @@
+package xcap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/zn-chen/xcap/internal/errcode"
+)
+
+// darwin 和 windows 后端都通过 internal/errcode 报告错误，
+// 这里覆盖所有错误码和 Go 层错误到公开错误的映射
+func TestNewErrorMapping(t *testing.T) {
+	for _, c := range []struct {
+		name   string
+		target int
+		err    error
+		kind   error
+		code   int
+	}{
+		{"no monitors", targetMonitor, &errcode.Error{Op: "get monitors", Code: errcode.NoMonitors}, ErrNoMonitor, 1},
+		{"no windows", targetWindow, &errcode.Error{Op: "get windows", Code: errcode.NoWindows}, ErrNoWindow, 2},
+		{"capture failed", targetMonitor, &errcode.Error{Op: "capture monitor", Code: errcode.CaptureFailed}, ErrCaptureFailed, 3},
+		{"alloc failed", targetWindow, &errcode.Error{Op: "capture window", Code: errcode.AllocFailed}, ErrCaptureFailed, 4},
+		{"monitor not found", targetMonitor, &errcode.Error{Op: "capture monitor", Code: errcode.NotFound}, ErrNoMonitor, 5},
+		{"permission denied", targetMonitor, &errcode.Error{Op: "capture monitor", Code: errcode.PermissionDenied}, ErrPermissionDenied, 6},
+		{"window not found", targetWindow, &errcode.Error{Op: "capture window", Code: errcode.NotFound}, ErrNoWindow, 5},
+		{"unknown code", targetWindow, &errcode.Error{Op: "capture window", Code: 42}, ErrCaptureFailed, 42},
+		{"not supported", targetWindow, errcode.ErrNotSupported, ErrNotSupported, 0},
+		{"invalid region", targetMonitor, errcode.ErrInvalidRegion, ErrInvalidRegion, 0},
+		{"wrapped backend error", targetMonitor, fmt.Errorf("context: %w", &errcode.Error{Op: "capture monitor", Code: errcode.NoMonitors}), ErrNoMonitor, 1},
+		{"plain error", targetMonitor, errors.New("boom"), ErrCaptureFailed, 0},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			err := newError("op", c.target, 7, c.err)
+
+			if !errors.Is(err, c.kind) {
+				t.Errorf("errors.Is(%v, %v) = false", err, c.kind)
+			}
+			if !errors.Is(err, c.err) {
+				t.Errorf("cause %v is not reachable through %v", c.err, err)
+			}
+
+			var xe *Error
+			if !errors.As(err, &xe) {
+				t.Fatalf("errors.As(*Error) failed for %v", err)
+			}
+			if xe.Op != "op" || xe.ID != 7 || xe.Code != c.code {
+				t.Errorf("Error = {Op: %q, ID: %d, Code: %d}, want {op, 7, %d}", xe.Op, xe.ID, xe.Code, c.code)
+			}
+
+			// 只映射到一个公开错误
+			for _, other := range []error{ErrNoMonitor, ErrNoWindow, ErrCaptureFailed, ErrPermissionDenied, ErrNotSupported, ErrInvalidRegion} {
+				if other != c.kind && errors.Is(err, other) {
+					t.Errorf("%v unexpectedly matches %v", err, other)
+				}
+			}
+		})
+	}
+}
+
+func TestNewErrorPassThrough(t *testing.T) {
+	if err := monitorError("op", 1, nil); err != nil {
+		t.Errorf("nil error wrapped as %v", err)
+	}
+
+	inner := windowError("capture window", 3, &errcode.Error{Op: "capture window", Code: errcode.CaptureFailed})
+	if err := windowError("outer", 9, inner); err != inner {
+		t.Errorf("already wrapped error was wrapped again: %v", err)
+	}
+
+	if got, want := inner.Error(), "xcap: capture window 3: failed to capture window: error code 3 (capture failed)"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+
+	// 超时不是平台错误，CaptureImageContext 原样返回 ctx.Err()
+	if err := monitorError("capture monitor", 1, context.DeadlineExceeded); err != context.DeadlineExceeded {
+		t.Errorf("context error wrapped as %v", err)
+	}
+}
+
+func TestWindowClosedError(t *testing.T) {
+	// 对已有窗口的操作返回 ErrWindowClosed，枚举类操作（ID 为 0）仍为 ErrNoWindow
+	notFound := &errcode.Error{Op: "capture window", Code: errcode.NotFound}
+
+	err := windowError("capture window", 9, notFound)
+	if !errors.Is(err, ErrWindowClosed) || !errors.Is(err, ErrNoWindow) {
+		t.Errorf("closed window error %v should match ErrWindowClosed and ErrNoWindow", err)
+	}
+
+	err = windowError("get window", 0, notFound)
+	if errors.Is(err, ErrWindowClosed) || !errors.Is(err, ErrNoWindow) {
+		t.Errorf("lookup error %v should match only ErrNoWindow", err)
+	}
+
+	if errors.Is(ErrNoWindow, ErrWindowClosed) {
+		t.Error("ErrNoWindow must not match ErrWindowClosed")
+	}
+}
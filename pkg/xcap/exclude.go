@@ -0,0 +1,145 @@
+package xcap
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math"
+)
+
+// excludingCapturer 由支持原生排除窗口截图的显示器实现
+type excludingCapturer interface {
+	captureExcluding(windowIDs []uint32) (*image.RGBA, error)
+}
+
+// CaptureMonitorExcluding 截取显示器，但画面中不包含 exclude 中的窗口
+//
+// 与 AllWindowsWithOptions 只过滤窗口列表不同，这里会重新合成显示器画面，
+// 适合截图时隐藏自身的浮层、工具栏等窗口。
+//
+// macOS 上由窗口服务器直接合成其余窗口。其他平台上先截取整个显示器，
+// 再按 Z 顺序用被排除窗口后面的窗口的单独截图覆盖被排除窗口的可见部分；
+// 后面没有窗口的部分（桌面背景）无法单独截取，以不透明黑色填充。
+func CaptureMonitorExcluding(m Monitor, exclude []Window) (*image.RGBA, error) {
+	if len(exclude) == 0 {
+		return m.CaptureImage()
+	}
+
+	ids := make([]uint32, len(exclude))
+	for i, w := range exclude {
+		ids[i] = w.ID()
+	}
+
+	if c, ok := m.(excludingCapturer); ok {
+		img, err := c.captureExcluding(ids)
+		if !errors.Is(err, ErrNotSupported) {
+			return img, err
+		}
+	}
+
+	windows, err := AllWindows()
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := m.CaptureImage()
+	if err != nil {
+		return nil, err
+	}
+
+	composeExcluding(img, monitorRect(m), windows, ids)
+	return img, nil
+}
+
+// composeExcluding 在覆盖全局区域 area 的截图 img 上去掉 excludeIDs 中的窗口
+func composeExcluding(img *image.RGBA, area image.Rectangle, windows []Window, excludeIDs []uint32) {
+	excluded := make(map[uint32]bool, len(excludeIDs))
+	for _, id := range excludeIDs {
+		excluded[id] = true
+	}
+
+	sorted := sortFrontToBack(windows)
+	rects := make([]image.Rectangle, len(sorted))
+	for i, w := range sorted {
+		rects[i] = windowRect(w).Intersect(area)
+	}
+
+	// 需要重绘的区域：被排除窗口在画面中的可见部分
+	var holes []image.Rectangle
+	for i, parts := range visibleRegions(rects) {
+		if excluded[sorted[i].ID()] {
+			holes = append(holes, parts...)
+		}
+	}
+
+	// 每个洞由其后面最靠前的未排除窗口填充。被排除窗口前面的窗口不会与其可见部分相交，
+	// 因此按从前到后的顺序遍历所有未排除窗口即可
+	for i, w := range sorted {
+		if len(holes) == 0 {
+			return
+		}
+		if excluded[w.ID()] || rects[i].Empty() {
+			continue
+		}
+
+		var covered []image.Rectangle
+		for _, h := range holes {
+			if in := h.Intersect(rects[i]); !in.Empty() {
+				covered = append(covered, in)
+			}
+		}
+		if len(covered) == 0 {
+			continue
+		}
+
+		src, err := w.CaptureImage()
+		for _, r := range covered {
+			if err != nil {
+				// 无法截取的窗口（如受保护的内容）不能透出被排除窗口，以黑色填充
+				fillRect(img, toPixelRect(r, area, img), color.RGBA{A: 255})
+				continue
+			}
+			copyRegion(img, area, src, windowRect(w), r)
+		}
+		holes = subtractRects(holes, rects[i])
+	}
+
+	for _, h := range holes {
+		fillRect(img, toPixelRect(h, area, img), color.RGBA{A: 255})
+	}
+}
+
+// copyRegion 将全局区域 r 的内容从 src（覆盖 srcArea）按最近邻复制到 dst（覆盖 dstArea）
+func copyRegion(dst *image.RGBA, dstArea image.Rectangle, src *image.RGBA, srcArea, r image.Rectangle) {
+	db, sb := dst.Bounds(), src.Bounds()
+	if sb.Empty() || srcArea.Empty() {
+		return
+	}
+
+	dsx := float64(dstArea.Dx()) / float64(db.Dx())
+	dsy := float64(dstArea.Dy()) / float64(db.Dy())
+	ssx := float64(sb.Dx()) / float64(srcArea.Dx())
+	ssy := float64(sb.Dy()) / float64(srcArea.Dy())
+
+	clamp := func(v, lo, hi int) int {
+		if v < lo {
+			return lo
+		}
+		if v >= hi {
+			return hi - 1
+		}
+		return v
+	}
+
+	p := toPixelRect(r, dstArea, dst)
+	for y := p.Min.Y; y < p.Max.Y; y++ {
+		gy := float64(dstArea.Min.Y) + (float64(y-db.Min.Y)+0.5)*dsy
+		sy := clamp(sb.Min.Y+int(math.Floor((gy-float64(srcArea.Min.Y))*ssy)), sb.Min.Y, sb.Max.Y)
+		row := dst.Pix[dst.PixOffset(p.Min.X, y):]
+		for x := 0; x < p.Dx(); x++ {
+			gx := float64(dstArea.Min.X) + (float64(p.Min.X+x-db.Min.X)+0.5)*dsx
+			sx := clamp(sb.Min.X+int(math.Floor((gx-float64(srcArea.Min.X))*ssx)), sb.Min.X, sb.Max.X)
+			copy(row[x*4:x*4+4], src.Pix[src.PixOffset(sx, sy):])
+		}
+	}
+}
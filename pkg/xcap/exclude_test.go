@@ -0,0 +1,82 @@
+package xcap
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// solidImage 返回纯色图像
+func solidImage(w, h int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	fillRect(img, img.Bounds(), c)
+	return img
+}
+
+func TestCaptureMonitorExcludingComposite(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	green := color.RGBA{0, 255, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	black := color.RGBA{A: 255}
+
+	// 2x 缩放的显示器，几何 100x100。浮层 (20,20)-(60,60) 在最前面，
+	// 其后是编辑器 (40,40)-(90,90)，再后面是桌面
+	overlay := &fakeWindow{id: 1, x: 20, y: 20, width: 40, height: 40, img: solidImage(80, 80, red)}
+	editor := &fakeWindow{id: 2, x: 40, y: 40, width: 50, height: 50, img: solidImage(100, 100, green)}
+
+	screen := solidImage(200, 200, blue)
+	fillRect(screen, image.Rect(80, 80, 180, 180), green)
+	fillRect(screen, image.Rect(40, 40, 120, 120), red)
+
+	m := &fakeMonitor{width: 100, height: 100, scaleFactor: 2, img: screen}
+	useFakeBackend(t, &fakeBackend{monitors: []Monitor{m}, windows: []Window{overlay, editor}})
+
+	img, err := CaptureMonitorExcluding(m, []Window{overlay})
+	if err != nil {
+		t.Fatalf("CaptureMonitorExcluding failed: %v", err)
+	}
+
+	for _, c := range []struct {
+		x, y int
+		want color.RGBA
+	}{
+		{100, 100, green}, // 浮层下面是编辑器
+		{80, 80, green},   // 编辑器左上角
+		{50, 50, black},   // 浮层下面没有窗口
+		{79, 119, black},
+		{10, 10, blue},    // 浮层之外保持不变
+		{150, 150, green}, // 编辑器未被遮挡的部分
+	} {
+		if got := img.RGBAAt(c.x, c.y); got != c.want {
+			t.Errorf("pixel (%d, %d) = %v, want %v", c.x, c.y, got, c.want)
+		}
+	}
+}
+
+// nativeExcludingMonitor 模拟支持原生排除窗口的后端
+type nativeExcludingMonitor struct {
+	*fakeMonitor
+	excluded []uint32
+}
+
+func (m *nativeExcludingMonitor) captureExcluding(windowIDs []uint32) (*image.RGBA, error) {
+	m.excluded = windowIDs
+	return m.CaptureImage()
+}
+
+func TestCaptureMonitorExcludingNative(t *testing.T) {
+	m := &nativeExcludingMonitor{fakeMonitor: &fakeMonitor{width: 10, height: 10, img: solidImage(10, 10, color.RGBA{1, 2, 3, 255})}}
+	// 原生路径不需要枚举窗口
+	useFakeBackend(t, &fakeBackend{})
+
+	img, err := CaptureMonitorExcluding(m, []Window{&fakeWindow{id: 7}, &fakeWindow{id: 9}})
+	if err != nil {
+		t.Fatalf("CaptureMonitorExcluding failed: %v", err)
+	}
+	if len(m.excluded) != 2 || m.excluded[0] != 7 || m.excluded[1] != 9 {
+		t.Errorf("native exclusion got IDs %v, want [7 9]", m.excluded)
+	}
+	if got := img.RGBAAt(5, 5); got != (color.RGBA{1, 2, 3, 255}) {
+		t.Errorf("pixel = %v, want the native capture", got)
+	}
+}
@@ -0,0 +1,275 @@
+package xcap
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/zn-chen/xcap/internal/errcode"
+)
+
+// fakeMonitor 是测试用的 Monitor 实现，截图返回预先设置的图像
+type fakeMonitor struct {
+	id          uint32
+	name        string
+	x, y        int
+	width       uint32
+	height      uint32
+	scaleFactor float32
+	rotation    float32
+	primary     bool
+	edid        []byte
+	img         *image.RGBA
+	err         error
+
+	// delay 截图前等待的时间，用于模拟卡住的平台调用
+	delay time.Duration
+
+	// regionSupported 为 true 时 CaptureRegion 从 img 中裁剪，否则返回 ErrNotSupported
+	regionSupported bool
+}
+
+func (m *fakeMonitor) ID() uint32           { return m.id }
+func (m *fakeMonitor) Name() string         { return m.name }
+func (m *fakeMonitor) X() int               { return m.x }
+func (m *fakeMonitor) Y() int               { return m.y }
+func (m *fakeMonitor) Width() uint32        { return m.width }
+func (m *fakeMonitor) Height() uint32       { return m.height }
+func (m *fakeMonitor) Rotation() float32    { return m.rotation }
+func (m *fakeMonitor) ScaleFactor() float32 { return m.scaleFactor }
+func (m *fakeMonitor) Frequency() float32   { return 60 }
+func (m *fakeMonitor) IsPrimary() bool      { return m.primary }
+func (m *fakeMonitor) IsBuiltin() bool      { return false }
+
+func (m *fakeMonitor) StableID() string {
+	return monitorStableID(m)
+}
+
+func (m *fakeMonitor) Info() (*MonitorInfo, error) {
+	return newMonitorInfo(nativeMonitorInfo{name: m.name, edid: m.edid}), nil
+}
+
+func (m *fakeMonitor) CaptureImage() (*image.RGBA, error) {
+	return m.CaptureImageContext(context.Background())
+}
+
+func (m *fakeMonitor) CaptureImageContext(ctx context.Context) (*image.RGBA, error) {
+	if err := sleepContext(ctx, m.delay); err != nil {
+		return nil, err
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	return cropRGBA(m.img, m.img.Bounds()), nil
+}
+
+func (m *fakeMonitor) CaptureRegion(x, y, width, height uint32) (*image.RGBA, error) {
+	if !m.regionSupported {
+		return nil, ErrNotSupported
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	if width == 0 || height == 0 || x+width > m.width || y+height > m.height {
+		return nil, ErrInvalidRegion
+	}
+
+	// 与真实后端一致：区域按几何坐标指定，返回的图像为像素尺寸
+	sx, sy := m.img.Bounds().Dx()/int(m.width), m.img.Bounds().Dy()/int(m.height)
+	r := image.Rect(int(x)*sx, int(y)*sy, int(x+width)*sx, int(y+height)*sy)
+	return cropRGBA(m.img, r), nil
+}
+
+func (m *fakeMonitor) PixelAt(x, y int) (color.RGBA, error) {
+	return monitorPixelAt(m, x, y)
+}
+
+// fakeWindow 是测试用的 Window 实现
+type fakeWindow struct {
+	id        uint32
+	pid       uint32
+	appName   string
+	title     string
+	x, y, z   int
+	width     uint32
+	height    uint32
+	minimized bool
+	img       *image.RGBA
+	err       error
+	delay     time.Duration
+
+	// closed 为 true 时模拟枚举后已关闭的窗口
+	closed bool
+	// refreshed 不为 nil 时 Refresh 从中读取新的标题和几何信息
+	refreshed *fakeWindow
+}
+
+func (w *fakeWindow) ID() uint32                   { return w.id }
+func (w *fakeWindow) PID() uint32                  { return w.pid }
+func (w *fakeWindow) AppName() string              { return w.appName }
+func (w *fakeWindow) Title() string                { return w.title }
+func (w *fakeWindow) X() int                       { return w.x }
+func (w *fakeWindow) Y() int                       { return w.y }
+func (w *fakeWindow) Z() int                       { return w.z }
+func (w *fakeWindow) Width() uint32                { return w.width }
+func (w *fakeWindow) Height() uint32               { return w.height }
+func (w *fakeWindow) IsMinimized() (bool, error)   { return w.minimized, nil }
+func (w *fakeWindow) IsMaximized() (bool, error)   { return false, nil }
+func (w *fakeWindow) IsFocused() (bool, error)     { return false, nil }
+func (w *fakeWindow) Class() (string, error)       { return "", ErrNotSupported }
+func (w *fakeWindow) Type() (WindowType, error)    { return WindowTypeNormal, nil }
+func (w *fakeWindow) ProcessPath() (string, error) { return "", ErrNotSupported }
+func (w *fakeWindow) OwnerID() (uint32, error)     { return 0, nil }
+func (w *fakeWindow) ParentID() (uint32, error)    { return 0, nil }
+func (w *fakeWindow) IsModal() (bool, error)       { return false, nil }
+func (w *fakeWindow) Opacity() (float64, error)    { return 1, nil }
+
+func (w *fakeWindow) Bounds(area WindowArea) (image.Rectangle, error) {
+	if area != WindowAreaFrame {
+		return image.Rectangle{}, ErrNotSupported
+	}
+	return windowRect(w), nil
+}
+
+func (w *fakeWindow) Refresh() error {
+	if w.closed {
+		return ErrWindowClosed
+	}
+	if r := w.refreshed; r != nil {
+		w.title, w.x, w.y, w.width, w.height = r.title, r.x, r.y, r.width, r.height
+	}
+	return nil
+}
+
+func (w *fakeWindow) Exists() (bool, error) {
+	if w.closed {
+		return windowExists(ErrWindowClosed)
+	}
+	return true, nil
+}
+
+func (w *fakeWindow) CurrentMonitor() (Monitor, error) {
+	return nil, ErrNotSupported
+}
+
+func (w *fakeWindow) CaptureImage() (*image.RGBA, error) {
+	return w.CaptureImageContext(context.Background())
+}
+
+func (w *fakeWindow) CaptureImageContext(ctx context.Context) (*image.RGBA, error) {
+	if err := sleepContext(ctx, w.delay); err != nil {
+		return nil, err
+	}
+	if w.closed {
+		return nil, ErrWindowClosed
+	}
+	if w.err != nil {
+		return nil, w.err
+	}
+	return cropRGBA(w.img, w.img.Bounds()), nil
+}
+
+func (w *fakeWindow) VisibleRegion() ([]image.Rectangle, error) {
+	return windowVisibleRegion(w)
+}
+
+func (w *fakeWindow) IsOccluded() (bool, error) {
+	return windowIsOccluded(w)
+}
+
+// fakeBackend 是测试用的 backend，返回预先设置的显示器和窗口
+type fakeBackend struct {
+	monitors []Monitor
+	windows  []Window
+	// hidden 是 windowByID 能找到、但 allWindows 不返回的窗口（隐藏、cloaked、工具窗口）
+	hidden     []Window
+	permission PermissionStatus
+	requested  bool
+
+	// delay 枚举前等待的时间
+	delay time.Duration
+
+	// cursorPos 和 cursor 为 CursorPosition/CursorImage 的返回值，cursor 为 nil 时返回 ErrNotSupported
+	cursorPos image.Point
+	cursor    *Cursor
+
+	// space 几何方法使用的坐标空间，零值为 SpaceLogical（与 macOS 相同）
+	space Space
+}
+
+func (b *fakeBackend) allMonitors(ctx context.Context) ([]Monitor, error) {
+	if err := sleepContext(ctx, b.delay); err != nil {
+		return nil, err
+	}
+	return b.monitors, nil
+}
+
+func (b *fakeBackend) allWindows(ctx context.Context, excludeCurrentProcess bool) ([]Window, error) {
+	if err := sleepContext(ctx, b.delay); err != nil {
+		return nil, err
+	}
+	return b.windows, nil
+}
+
+func (b *fakeBackend) windowByID(id uint32) (Window, error) {
+	for _, list := range [][]Window{b.windows, b.hidden} {
+		for _, w := range list {
+			if w.ID() == id {
+				if ok, _ := w.Exists(); ok {
+					return w, nil
+				}
+			}
+		}
+	}
+	return nil, windowError("get window", id, &errcode.Error{Op: "get window info", Code: errcode.NotFound})
+}
+
+// sleepContext 模拟耗时 d 的平台调用，ctx 先结束时返回 ctx.Err()
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *fakeBackend) checkPermission() PermissionStatus {
+	return b.permission
+}
+
+func (b *fakeBackend) requestPermission() PermissionStatus {
+	b.requested = true
+	return b.permission
+}
+
+func (b *fakeBackend) cursorPosition() (image.Point, error) {
+	if b.cursor == nil {
+		return image.Point{}, ErrNotSupported
+	}
+	return b.cursorPos, nil
+}
+
+func (b *fakeBackend) cursorImage() (*Cursor, error) {
+	if b.cursor == nil {
+		return nil, ErrNotSupported
+	}
+	return b.cursor, nil
+}
+
+func (b *fakeBackend) coordinateSpace() Space {
+	return b.space
+}
+
+// useFakeBackend 在测试期间用 b 替换平台后端
+func useFakeBackend(t *testing.T, b *fakeBackend) {
+	t.Helper()
+	prev := platform
+	platform = b
+	t.Cleanup(func() { platform = prev })
+}
@@ -0,0 +1,239 @@
+package xcap
+
+import (
+	"image"
+	"math"
+)
+
+// Space 表示全局坐标空间
+type Space int
+
+const (
+	// SpaceLogical 逻辑坐标（点），物理像素 = 逻辑坐标 × ScaleFactor
+	// macOS 的 Monitor/Window 几何方法使用该空间
+	SpaceLogical Space = iota
+
+	// SpacePhysical 物理像素坐标，与截图的像素一一对应
+	// Windows（每显示器 DPI 感知）的 Monitor/Window 几何方法使用该空间
+	SpacePhysical
+)
+
+// String 返回坐标空间的名称
+func (s Space) String() string {
+	if s == SpacePhysical {
+		return "physical"
+	}
+	return "logical"
+}
+
+// NativeSpace 返回当前平台 Monitor.X()/Y()/Width()/Height() 和 Window 对应方法使用的坐标空间
+func NativeSpace() Space {
+	return platform.coordinateSpace()
+}
+
+// MonitorGeometry 描述一个显示器在两个全局坐标空间中的位置
+//
+// 两个空间中显示器的左上角相同，只有尺寸按 Scale 缩放。
+// 因此在缩放比例不同的多显示器布局中，物理空间里相邻的显示器之间可能出现空隙或重叠，
+// 跨显示器的转换总是以点所在的显示器为准。
+type MonitorGeometry struct {
+	Monitor Monitor
+
+	// Logical 显示器在全局逻辑空间中的矩形
+	Logical image.Rectangle
+
+	// Physical 显示器在全局物理空间中的矩形，尺寸与 CaptureImage 的结果相同
+	Physical image.Rectangle
+
+	// Scale 每个逻辑单位对应的物理像素数，即 ScaleFactor（为 0 时视为 1）
+	Scale float64
+}
+
+// Geometry 在全局逻辑、全局物理、显示器内像素和窗口内像素坐标之间转换
+//
+// 显示器内和窗口内坐标指 CaptureImage 返回图像中的像素坐标，
+// 原点为显示器或窗口的左上角。全局坐标参数使用 Native 空间，
+// 即与 Monitor.X()、Window.X() 等方法相同的坐标；
+// 需要另一个空间时先用 ToPhysical/ToLogical 转换。
+type Geometry struct {
+	// Native 几何方法使用的全局坐标空间
+	Native Space
+
+	// Monitors 各显示器的几何信息
+	Monitors []MonitorGeometry
+}
+
+// CurrentGeometry 返回当前显示器布局的 Geometry
+func CurrentGeometry() (*Geometry, error) {
+	monitors, err := AllMonitors()
+	if err != nil {
+		return nil, err
+	}
+	return NewGeometry(monitors), nil
+}
+
+// NewGeometry 根据 monitors 的几何信息创建 Geometry，坐标空间为 NativeSpace()
+func NewGeometry(monitors []Monitor) *Geometry {
+	g := &Geometry{Native: NativeSpace(), Monitors: make([]MonitorGeometry, len(monitors))}
+
+	for i, m := range monitors {
+		scale := float64(m.ScaleFactor())
+		if scale <= 0 {
+			scale = 1
+		}
+
+		r := monitorRect(m)
+		mg := MonitorGeometry{Monitor: m, Scale: scale}
+		if g.Native == SpacePhysical {
+			mg.Physical = r
+			mg.Logical = image.Rectangle{Min: r.Min, Max: r.Min.Add(scaleSize(r.Size(), 1/scale))}
+		} else {
+			mg.Logical = r
+			mg.Physical = image.Rectangle{Min: r.Min, Max: r.Min.Add(scaleSize(r.Size(), scale))}
+		}
+		g.Monitors[i] = mg
+	}
+
+	return g
+}
+
+// MonitorAt 返回在 space 空间中包含 p 的显示器，没有时返回 nil
+func (g *Geometry) MonitorAt(p image.Point, space Space) *MonitorGeometry {
+	for i := range g.Monitors {
+		if p.In(g.Monitors[i].rect(space)) {
+			return &g.Monitors[i]
+		}
+	}
+	return nil
+}
+
+// ToPhysical 将全局逻辑坐标转换为全局物理坐标
+// p 不在任何显示器内时返回 false
+func (g *Geometry) ToPhysical(p image.Point) (image.Point, bool) {
+	m := g.MonitorAt(p, SpaceLogical)
+	if m == nil {
+		return p, false
+	}
+	return m.Physical.Min.Add(scalePoint(p.Sub(m.Logical.Min), m.Scale)), true
+}
+
+// ToLogical 将全局物理坐标转换为全局逻辑坐标，结果为该像素所在的逻辑坐标
+// p 不在任何显示器内时返回 false
+func (g *Geometry) ToLogical(p image.Point) (image.Point, bool) {
+	m := g.MonitorAt(p, SpacePhysical)
+	if m == nil {
+		return p, false
+	}
+	return m.Logical.Min.Add(scalePoint(p.Sub(m.Physical.Min), 1/m.Scale)), true
+}
+
+// ToPhysicalRect 将全局逻辑矩形转换为全局物理矩形，以矩形中心所在的显示器为准
+func (g *Geometry) ToPhysicalRect(r image.Rectangle) (image.Rectangle, bool) {
+	m := g.MonitorAt(rectCenter(r), SpaceLogical)
+	if m == nil {
+		return r, false
+	}
+	return scaleRectAround(r, m.Logical.Min, m.Physical.Min, m.Scale), true
+}
+
+// ToLogicalRect 将全局物理矩形转换为全局逻辑矩形，以矩形中心所在的显示器为准
+func (g *Geometry) ToLogicalRect(r image.Rectangle) (image.Rectangle, bool) {
+	m := g.MonitorAt(rectCenter(r), SpacePhysical)
+	if m == nil {
+		return r, false
+	}
+	return scaleRectAround(r, m.Physical.Min, m.Logical.Min, 1/m.Scale), true
+}
+
+// GlobalToMonitor 将全局坐标 p（Native 空间）转换为显示器 m 截图中的像素坐标
+// 结果可能位于截图范围之外
+func (g *Geometry) GlobalToMonitor(m Monitor, p image.Point) image.Point {
+	return scalePoint(p.Sub(image.Pt(m.X(), m.Y())), g.pixelScale(g.find(m)))
+}
+
+// MonitorToGlobal 将显示器 m 截图中的像素坐标转换为全局坐标（Native 空间）
+func (g *Geometry) MonitorToGlobal(m Monitor, local image.Point) image.Point {
+	return image.Pt(m.X(), m.Y()).Add(scalePoint(local, 1/g.pixelScale(g.find(m))))
+}
+
+// GlobalToWindow 将全局坐标 p（Native 空间）转换为窗口 w 截图中的像素坐标
+// 窗口的缩放比例取其中心所在的显示器
+func (g *Geometry) GlobalToWindow(w Window, p image.Point) image.Point {
+	return scalePoint(p.Sub(image.Pt(w.X(), w.Y())), g.windowScale(w))
+}
+
+// WindowToGlobal 将窗口 w 截图中的像素坐标转换为全局坐标（Native 空间）
+func (g *Geometry) WindowToGlobal(w Window, local image.Point) image.Point {
+	return image.Pt(w.X(), w.Y()).Add(scalePoint(local, 1/g.windowScale(w)))
+}
+
+// rect 返回显示器在 space 空间中的矩形
+func (m *MonitorGeometry) rect(space Space) image.Rectangle {
+	if space == SpacePhysical {
+		return m.Physical
+	}
+	return m.Logical
+}
+
+// find 返回 m 对应的 MonitorGeometry，m 不属于 g 时按 ID 查找，仍找不到时返回 nil
+func (g *Geometry) find(m Monitor) *MonitorGeometry {
+	for i := range g.Monitors {
+		if g.Monitors[i].Monitor == m {
+			return &g.Monitors[i]
+		}
+	}
+	for i := range g.Monitors {
+		if g.Monitors[i].Monitor.ID() == m.ID() {
+			return &g.Monitors[i]
+		}
+	}
+	return nil
+}
+
+// pixelScale 返回 Native 空间中每个单位对应的截图像素数
+func (g *Geometry) pixelScale(m *MonitorGeometry) float64 {
+	if m == nil || g.Native == SpacePhysical {
+		return 1
+	}
+	return m.Scale
+}
+
+// windowScale 返回窗口截图中每个 Native 单位对应的像素数
+func (g *Geometry) windowScale(w Window) float64 {
+	return g.pixelScale(g.MonitorAt(rectCenter(windowRect(w)), g.Native))
+}
+
+// rectCenter 返回矩形的中心点
+func rectCenter(r image.Rectangle) image.Point {
+	return image.Pt((r.Min.X+r.Max.X)/2, (r.Min.Y+r.Max.Y)/2)
+}
+
+// geometryEpsilon 取整前的容差，避免 1/1.5 这类比例的浮点误差使整数结果少 1
+const geometryEpsilon = 1e-9
+
+// scalePoint 将 p 乘以 k，向下取整，使结果为 p 所在的像素（或点）
+func scalePoint(p image.Point, k float64) image.Point {
+	return image.Pt(floorScale(p.X, k), floorScale(p.Y, k))
+}
+
+func floorScale(v int, k float64) int {
+	return int(math.Floor(float64(v)*k + geometryEpsilon))
+}
+
+func ceilScale(v int, k float64) int {
+	return int(math.Ceil(float64(v)*k - geometryEpsilon))
+}
+
+// scaleSize 将尺寸乘以 k 并四舍五入
+func scaleSize(p image.Point, k float64) image.Point {
+	return image.Pt(int(math.Round(float64(p.X)*k)), int(math.Round(float64(p.Y)*k)))
+}
+
+// scaleRectAround 将 r 从以 from 为原点的空间缩放到以 to 为原点的空间，结果包含 r 覆盖的全部区域
+func scaleRectAround(r image.Rectangle, from, to image.Point, k float64) image.Rectangle {
+	r = r.Sub(from)
+	return image.Rect(
+		floorScale(r.Min.X, k), floorScale(r.Min.Y, k),
+		ceilScale(r.Max.X, k), ceilScale(r.Max.Y, k),
+	).Add(to)
+}
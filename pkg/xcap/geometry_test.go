@@ -0,0 +1,119 @@
+package xcap
+
+import (
+	"image"
+	"testing"
+)
+
+// macOS 风格的混合 DPI 布局（逻辑坐标）：左侧 2x 的 Retina 屏 100x100 点，右侧 1x 屏 200x100 点
+func logicalLayout(t *testing.T) (*Geometry, *fakeMonitor, *fakeMonitor) {
+	retina := &fakeMonitor{id: 1, width: 100, height: 100, scaleFactor: 2}
+	external := &fakeMonitor{id: 2, x: 100, width: 200, height: 100, scaleFactor: 1}
+	useFakeBackend(t, &fakeBackend{monitors: []Monitor{retina, external}})
+
+	g, err := CurrentGeometry()
+	if err != nil {
+		t.Fatalf("CurrentGeometry failed: %v", err)
+	}
+	return g, retina, external
+}
+
+func TestGeometryLogicalLayout(t *testing.T) {
+	g, retina, external := logicalLayout(t)
+
+	if g.Native != SpaceLogical {
+		t.Fatalf("Native = %v, want logical", g.Native)
+	}
+	if got := g.Monitors[0].Physical; got != image.Rect(0, 0, 200, 200) {
+		t.Errorf("retina physical = %v", got)
+	}
+	if got := g.Monitors[1].Physical; got != image.Rect(100, 0, 300, 100) {
+		t.Errorf("external physical = %v", got)
+	}
+
+	for _, c := range []struct {
+		logical, physical image.Point
+	}{
+		{image.Pt(50, 50), image.Pt(100, 100)},
+		{image.Pt(99, 99), image.Pt(198, 198)},
+		{image.Pt(150, 50), image.Pt(150, 50)},
+	} {
+		if got, ok := g.ToPhysical(c.logical); !ok || got != c.physical {
+			t.Errorf("ToPhysical(%v) = %v, %v; want %v", c.logical, got, ok, c.physical)
+		}
+	}
+
+	// 物理像素 (199, 199) 属于逻辑点 (99, 99)
+	if got, ok := g.ToLogical(image.Pt(199, 199)); !ok || got != image.Pt(99, 99) {
+		t.Errorf("ToLogical(199,199) = %v, %v", got, ok)
+	}
+	if _, ok := g.ToPhysical(image.Pt(500, 500)); ok {
+		t.Error("ToPhysical outside all monitors should fail")
+	}
+
+	if got, ok := g.ToPhysicalRect(image.Rect(10, 10, 20, 30)); !ok || got != image.Rect(20, 20, 40, 60) {
+		t.Errorf("ToPhysicalRect = %v, %v", got, ok)
+	}
+
+	// 截图像素与全局坐标互转
+	if got := g.GlobalToMonitor(retina, image.Pt(30, 40)); got != image.Pt(60, 80) {
+		t.Errorf("GlobalToMonitor(retina) = %v", got)
+	}
+	if got := g.MonitorToGlobal(retina, image.Pt(61, 81)); got != image.Pt(30, 40) {
+		t.Errorf("MonitorToGlobal(retina) = %v", got)
+	}
+	if got := g.GlobalToMonitor(external, image.Pt(130, 40)); got != image.Pt(30, 40) {
+		t.Errorf("GlobalToMonitor(external) = %v", got)
+	}
+}
+
+func TestGeometryWindowLocal(t *testing.T) {
+	g, _, _ := logicalLayout(t)
+
+	onRetina := &fakeWindow{x: 10, y: 10, width: 40, height: 40}
+	onExternal := &fakeWindow{x: 120, y: 10, width: 40, height: 40}
+
+	if got := g.GlobalToWindow(onRetina, image.Pt(20, 30)); got != image.Pt(20, 40) {
+		t.Errorf("GlobalToWindow(retina) = %v", got)
+	}
+	if got := g.WindowToGlobal(onRetina, image.Pt(20, 40)); got != image.Pt(20, 30) {
+		t.Errorf("WindowToGlobal(retina) = %v", got)
+	}
+	if got := g.GlobalToWindow(onExternal, image.Pt(130, 30)); got != image.Pt(10, 20) {
+		t.Errorf("GlobalToWindow(external) = %v", got)
+	}
+}
+
+// Windows 风格的混合 DPI 布局（物理像素）：左侧 200% 的 200x200，右侧 150% 的 150x150
+func TestGeometryPhysicalLayout(t *testing.T) {
+	left := &fakeMonitor{id: 1, width: 200, height: 200, scaleFactor: 2}
+	right := &fakeMonitor{id: 2, x: 200, width: 150, height: 150, scaleFactor: 1.5}
+	useFakeBackend(t, &fakeBackend{monitors: []Monitor{left, right}, space: SpacePhysical})
+
+	g, err := CurrentGeometry()
+	if err != nil {
+		t.Fatalf("CurrentGeometry failed: %v", err)
+	}
+	if got := g.Monitors[1].Logical; got != image.Rect(200, 0, 300, 100) {
+		t.Errorf("right logical = %v", got)
+	}
+
+	if got, ok := g.ToLogical(image.Pt(350-1, 150-1)); !ok || got != image.Pt(299, 99) {
+		t.Errorf("ToLogical = %v, %v", got, ok)
+	}
+	if got, ok := g.ToPhysical(image.Pt(300-1, 0)); !ok || got != image.Pt(348, 0) {
+		t.Errorf("ToPhysical = %v, %v", got, ok)
+	}
+	if got, ok := g.ToLogicalRect(image.Rect(200, 0, 350, 150)); !ok || got != image.Rect(200, 0, 300, 100) {
+		t.Errorf("ToLogicalRect = %v, %v", got, ok)
+	}
+
+	// 物理坐标下截图像素与全局坐标只差原点
+	if got := g.GlobalToMonitor(right, image.Pt(210, 20)); got != image.Pt(10, 20) {
+		t.Errorf("GlobalToMonitor = %v", got)
+	}
+	w := &fakeWindow{x: 220, y: 20, width: 50, height: 50}
+	if got := g.WindowToGlobal(w, image.Pt(5, 5)); got != image.Pt(225, 25) {
+		t.Errorf("WindowToGlobal = %v", got)
+	}
+}
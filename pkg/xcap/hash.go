@@ -0,0 +1,164 @@
+package xcap
+
+import (
+	"image"
+	"math"
+	"math/bits"
+	"sort"
+)
+
+// 感知哈希（perceptual hash）用于判断两张截图是否近似相同。
+// 三种算法均返回 64 位哈希，使用 HammingDistance 比较：
+// 距离越小越相似，通常 <= 5 可以视为同一画面。
+
+// AverageHash 计算 aHash：缩小为 8x8 灰度图，每个像素与平均值比较
+// 计算最快，但对整体亮度变化较敏感
+func AverageHash(img image.Image) uint64 {
+	gray := grayThumbnail(img, 8, 8)
+
+	var mean float64
+	for _, v := range gray {
+		mean += v
+	}
+	mean /= float64(len(gray))
+
+	var hash uint64
+	for i, v := range gray {
+		if v > mean {
+			hash |= 1 << uint(i)
+		}
+	}
+	return hash
+}
+
+// DifferenceHash 计算 dHash：缩小为 9x8 灰度图，比较每行相邻像素的明暗梯度
+// 对亮度和对比度的整体变化不敏感，适合检测画面内容的变化
+func DifferenceHash(img image.Image) uint64 {
+	gray := grayThumbnail(img, 9, 8)
+
+	var hash uint64
+	bit := 0
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			if gray[y*9+x] < gray[y*9+x+1] {
+				hash |= 1 << uint(bit)
+			}
+			bit++
+		}
+	}
+	return hash
+}
+
+// PerceptualHash 计算 pHash：缩小为 32x32 灰度图并做二维 DCT，
+// 取左上角 8x8 低频系数与其中位数比较
+// 计算最慢，但对缩放、压缩噪声和轻微的颜色变化最稳定
+func PerceptualHash(img image.Image) uint64 {
+	const size = 32
+	gray := grayThumbnail(img, size, size)
+
+	// 先对每行、再对每列做一维 DCT-II，只保留需要的 8 个低频分量
+	rows := make([]float64, size*8)
+	for y := 0; y < size; y++ {
+		for u := 0; u < 8; u++ {
+			rows[y*8+u] = dct(gray[y*size:(y+1)*size], u)
+		}
+	}
+
+	coeffs := make([]float64, 64)
+	col := make([]float64, size)
+	for u := 0; u < 8; u++ {
+		for y := 0; y < size; y++ {
+			col[y] = rows[y*8+u]
+		}
+		for v := 0; v < 8; v++ {
+			coeffs[v*8+u] = dct(col, v)
+		}
+	}
+
+	// 直流分量只反映平均亮度，不参与中位数计算
+	sorted := append([]float64(nil), coeffs[1:]...)
+	sort.Float64s(sorted)
+	median := (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+
+	var hash uint64
+	for i, c := range coeffs {
+		if c > median {
+			hash |= 1 << uint(i)
+		}
+	}
+	return hash
+}
+
+// HammingDistance 返回两个哈希之间不同的位数（0-64）
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// dct 计算序列 s 的第 k 个一维 DCT-II 系数（未归一化）
+func dct(s []float64, k int) float64 {
+	n := float64(len(s))
+	var sum float64
+	for i, v := range s {
+		sum += v * math.Cos(math.Pi/n*(float64(i)+0.5)*float64(k))
+	}
+	return sum
+}
+
+// grayThumbnail 按区域平均将图像缩小为 w x h 的灰度图（亮度取值 0-255）
+// 缩小前的每个源像素按其覆盖目标像素的比例计入，避免简单采样带来的混叠
+func grayThumbnail(img image.Image, w, h int) []float64 {
+	b := img.Bounds()
+	out := make([]float64, w*h)
+	if b.Empty() {
+		return out
+	}
+
+	sw, sh := b.Dx(), b.Dy()
+	weights := make([]float64, w*h)
+
+	// 截图结果均为 *image.RGBA，直接读取 Pix 避免逐像素的接口调用
+	luma := func(x, y int) float64 {
+		r, g, bl, _ := img.At(x, y).RGBA()
+		return (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(bl)) / 257
+	}
+	if rgba, ok := img.(*image.RGBA); ok {
+		luma = func(x, y int) float64 {
+			p := rgba.Pix[rgba.PixOffset(x, y):]
+			return 0.299*float64(p[0]) + 0.587*float64(p[1]) + 0.114*float64(p[2])
+		}
+	}
+
+	for sy := 0; sy < sh; sy++ {
+		y0 := float64(sy) * float64(h) / float64(sh)
+		y1 := float64(sy+1) * float64(h) / float64(sh)
+		for sx := 0; sx < sw; sx++ {
+			x0 := float64(sx) * float64(w) / float64(sw)
+			x1 := float64(sx+1) * float64(w) / float64(sw)
+
+			lum := luma(b.Min.X+sx, b.Min.Y+sy)
+
+			// 源像素可能跨越多个目标像素，按重叠面积分配权重
+			for ty := int(y0); ty < h && float64(ty) < y1; ty++ {
+				oy := math.Min(y1, float64(ty+1)) - math.Max(y0, float64(ty))
+				if oy <= 0 {
+					continue
+				}
+				for tx := int(x0); tx < w && float64(tx) < x1; tx++ {
+					ox := math.Min(x1, float64(tx+1)) - math.Max(x0, float64(tx))
+					if ox <= 0 {
+						continue
+					}
+					out[ty*w+tx] += lum * ox * oy
+					weights[ty*w+tx] += ox * oy
+				}
+			}
+		}
+	}
+
+	for i := range out {
+		if weights[i] > 0 {
+			out[i] /= weights[i]
+		}
+	}
+	return out
+}
@@ -0,0 +1,90 @@
+package xcap
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// gradientImage 生成水平灰度渐变加一个亮块的测试图像
+func gradientImage(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := uint8(x * 255 / w)
+			img.SetRGBA(x, y, color.RGBA{v, v, v, 255})
+		}
+	}
+	fillRect(img, image.Rect(w/8, h/4, w/3, h/2), color.RGBA{250, 250, 250, 255})
+	return img
+}
+
+// checkerImage 生成棋盘格测试图像
+func checkerImage(w, h, cell int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if (x/cell+y/cell)%2 == 0 {
+				img.SetRGBA(x, y, color.RGBA{255, 255, 255, 255})
+			} else {
+				img.SetRGBA(x, y, color.RGBA{0, 0, 0, 255})
+			}
+		}
+	}
+	return img
+}
+
+func TestHammingDistance(t *testing.T) {
+	tests := []struct {
+		a, b uint64
+		want int
+	}{
+		{0, 0, 0},
+		{0, 1, 1},
+		{0xFF, 0x0F, 4},
+		{0, ^uint64(0), 64},
+	}
+	for _, tt := range tests {
+		if got := HammingDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("HammingDistance(%#x, %#x) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestPerceptualHashes(t *testing.T) {
+	hashes := []struct {
+		name string
+		fn   func(image.Image) uint64
+	}{
+		{"AverageHash", AverageHash},
+		{"DifferenceHash", DifferenceHash},
+		{"PerceptualHash", PerceptualHash},
+	}
+
+	base := gradientImage(320, 200)
+
+	// 轻微变化：少量像素的颜色改变，以及不同分辨率的同一画面
+	noisy := gradientImage(320, 200)
+	fillRect(noisy, image.Rect(300, 180, 304, 184), color.RGBA{255, 0, 0, 255})
+	scaled := gradientImage(640, 400)
+
+	different := checkerImage(320, 200, 40)
+
+	for _, h := range hashes {
+		t.Run(h.name, func(t *testing.T) {
+			hb := h.fn(base)
+			if d := HammingDistance(hb, h.fn(base)); d != 0 {
+				t.Errorf("same image distance = %d, want 0", d)
+			}
+			if d := HammingDistance(hb, h.fn(noisy)); d > 5 {
+				t.Errorf("near-duplicate distance = %d, want <= 5", d)
+			}
+			if d := HammingDistance(hb, h.fn(scaled)); d > 5 {
+				t.Errorf("scaled image distance = %d, want <= 5", d)
+			}
+			if d := HammingDistance(hb, h.fn(different)); d < 10 {
+				t.Errorf("different image distance = %d, want >= 10", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,337 @@
+package xcap
+
+import (
+	"image"
+	"math"
+	"sort"
+)
+
+// 模板匹配默认参数
+const (
+	// DefaultLocateThreshold 默认的最低匹配置信度
+	DefaultLocateThreshold = 0.9
+
+	// DefaultLocateMaxResults 默认最多返回的匹配数
+	DefaultLocateMaxResults = 10
+
+	// locateMinLevelSize 金字塔顶层模板的最小边长，再缩小会丢失过多细节
+	locateMinLevelSize = 8
+
+	// locateCoarseSlack 顶层粗搜索时放宽的阈值，缩小后的相关系数通常偏低
+	locateCoarseSlack = 0.25
+
+	// locateRefineRadius 逐层细化时在上一层结果附近搜索的半径（像素）
+	locateRefineRadius = 2
+)
+
+// LocateOptions 控制模板匹配的行为
+type LocateOptions struct {
+	// Threshold 最低置信度（0-1），为 0 时使用 DefaultLocateThreshold
+	Threshold float64
+
+	// MaxResults 最多返回的匹配数，为 0 时使用 DefaultLocateMaxResults
+	MaxResults int
+}
+
+// Match 表示一个模板匹配结果
+type Match struct {
+	// Rect 匹配区域。Locate 返回 haystack 中的像素坐标，
+	// LocateOnMonitor/LocateInWindow 返回与 Monitor.X()/Y() 相同坐标系的全局桌面坐标
+	Rect image.Rectangle
+
+	// Confidence 归一化互相关系数（0-1），越大越相似
+	Confidence float64
+}
+
+// Locate 在 haystack 中查找 needle 出现的位置，按置信度从高到低返回
+//
+// 使用灰度图上的归一化互相关（NCC），对整体亮度和对比度的变化不敏感。
+// 为了避免在整幅截图上逐像素计算，先在缩小的图像金字塔顶层全局搜索，
+// 再逐层放大，只在候选位置附近细化。
+// needle 的透明通道会被忽略；needle 比 haystack 大时返回空结果。
+func Locate(haystack *image.RGBA, needle image.Image, opts LocateOptions) ([]Match, error) {
+	if needle.Bounds().Empty() {
+		return nil, ErrInvalidRegion
+	}
+	if opts.Threshold <= 0 {
+		opts.Threshold = DefaultLocateThreshold
+	}
+	if opts.MaxResults <= 0 {
+		opts.MaxResults = DefaultLocateMaxResults
+	}
+
+	hb, nb := haystack.Bounds(), needle.Bounds()
+	if nb.Dx() > hb.Dx() || nb.Dy() > hb.Dy() {
+		return nil, nil
+	}
+
+	// 构建金字塔：levels[0] 为原图，越往后越小
+	hay := []*grayImage{newGrayImage(haystack)}
+	ndl := []*grayImage{newGrayImage(needle)}
+	for {
+		n := ndl[len(ndl)-1]
+		if n.w/2 < locateMinLevelSize || n.h/2 < locateMinLevelSize {
+			break
+		}
+		hay = append(hay, hay[len(hay)-1].half())
+		ndl = append(ndl, n.half())
+	}
+
+	// 顶层全局搜索
+	top := len(hay) - 1
+	tmpl := newTemplate(ndl[top])
+	integral := newIntegral(hay[top])
+
+	var candidates []Match
+	coarse := opts.Threshold - locateCoarseSlack
+	for y := 0; y+tmpl.h <= hay[top].h; y++ {
+		for x := 0; x+tmpl.w <= hay[top].w; x++ {
+			if s := tmpl.score(hay[top], integral, x, y); s >= coarse {
+				candidates = append(candidates, Match{Rect: image.Rect(x, y, x+tmpl.w, y+tmpl.h), Confidence: s})
+			}
+		}
+	}
+	candidates = suppressOverlaps(candidates, opts.MaxResults*4)
+
+	// 逐层细化
+	for level := top - 1; level >= 0; level-- {
+		tmpl := newTemplate(ndl[level])
+		integral := newIntegral(hay[level])
+
+		for i, c := range candidates {
+			best := Match{Confidence: -1}
+			cx, cy := c.Rect.Min.X*2, c.Rect.Min.Y*2
+			for y := cy - locateRefineRadius; y <= cy+locateRefineRadius; y++ {
+				for x := cx - locateRefineRadius; x <= cx+locateRefineRadius; x++ {
+					if x < 0 || y < 0 || x+tmpl.w > hay[level].w || y+tmpl.h > hay[level].h {
+						continue
+					}
+					if s := tmpl.score(hay[level], integral, x, y); s > best.Confidence {
+						best = Match{Rect: image.Rect(x, y, x+tmpl.w, y+tmpl.h), Confidence: s}
+					}
+				}
+			}
+			candidates[i] = best
+		}
+	}
+
+	var matches []Match
+	for _, c := range candidates {
+		if c.Confidence >= opts.Threshold {
+			c.Rect = c.Rect.Add(hb.Min)
+			matches = append(matches, c)
+		}
+	}
+
+	return suppressOverlaps(matches, opts.MaxResults), nil
+}
+
+// LocateOnMonitor 截取显示器并查找 needle，返回全局桌面坐标
+func LocateOnMonitor(m Monitor, needle image.Image, opts LocateOptions) ([]Match, error) {
+	img, err := m.CaptureImage()
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := Locate(img, needle, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return toGlobalMatches(matches, img, m.X(), m.Y(), m.Width(), m.Height()), nil
+}
+
+// LocateInWindow 截取窗口并查找 needle，返回全局桌面坐标
+func LocateInWindow(w Window, needle image.Image, opts LocateOptions) ([]Match, error) {
+	img, err := w.CaptureImage()
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := Locate(img, needle, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return toGlobalMatches(matches, img, w.X(), w.Y(), w.Width(), w.Height()), nil
+}
+
+// toGlobalMatches 将截图内的像素坐标换算为全局坐标
+// 截图像素与几何尺寸的比例即缩放因子（如 macOS Retina 屏幕上截图为点坐标的 2 倍）
+func toGlobalMatches(matches []Match, img *image.RGBA, x, y int, width, height uint32) []Match {
+	sx, sy := 1.0, 1.0
+	if width > 0 && height > 0 {
+		sx = float64(img.Bounds().Dx()) / float64(width)
+		sy = float64(img.Bounds().Dy()) / float64(height)
+	}
+
+	b := img.Bounds()
+	for i, m := range matches {
+		r := m.Rect.Sub(b.Min)
+		matches[i].Rect = image.Rect(
+			x+int(math.Floor(float64(r.Min.X)/sx)),
+			y+int(math.Floor(float64(r.Min.Y)/sy)),
+			x+int(math.Ceil(float64(r.Max.X)/sx)),
+			y+int(math.Ceil(float64(r.Max.Y)/sy)),
+		)
+	}
+	return matches
+}
+
+// suppressOverlaps 按置信度从高到低保留匹配，丢弃与已保留结果重叠过半的匹配
+func suppressOverlaps(matches []Match, limit int) []Match {
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Confidence > matches[j].Confidence
+	})
+
+	var kept []Match
+	for _, m := range matches {
+		area := m.Rect.Dx() * m.Rect.Dy()
+		overlapped := false
+		for _, k := range kept {
+			in := m.Rect.Intersect(k.Rect)
+			if in.Dx()*in.Dy()*2 > area {
+				overlapped = true
+				break
+			}
+		}
+		if overlapped {
+			continue
+		}
+
+		kept = append(kept, m)
+		if len(kept) >= limit {
+			break
+		}
+	}
+	return kept
+}
+
+// grayImage 是用于匹配计算的浮点灰度图
+type grayImage struct {
+	w, h int
+	pix  []float64
+}
+
+func newGrayImage(img image.Image) *grayImage {
+	b := img.Bounds()
+	g := &grayImage{w: b.Dx(), h: b.Dy(), pix: make([]float64, b.Dx()*b.Dy())}
+
+	if rgba, ok := img.(*image.RGBA); ok {
+		for y := 0; y < g.h; y++ {
+			row := rgba.Pix[rgba.PixOffset(b.Min.X, b.Min.Y+y):]
+			for x := 0; x < g.w; x++ {
+				p := row[x*4:]
+				g.pix[y*g.w+x] = 0.299*float64(p[0]) + 0.587*float64(p[1]) + 0.114*float64(p[2])
+			}
+		}
+		return g
+	}
+
+	for y := 0; y < g.h; y++ {
+		for x := 0; x < g.w; x++ {
+			r, gr, bl, _ := img.At(b.Min.X+x, b.Min.Y+y).RGBA()
+			g.pix[y*g.w+x] = (0.299*float64(r) + 0.587*float64(gr) + 0.114*float64(bl)) / 257
+		}
+	}
+	return g
+}
+
+// half 以 2x2 平均缩小为一半尺寸
+func (g *grayImage) half() *grayImage {
+	h := &grayImage{w: g.w / 2, h: g.h / 2}
+	h.pix = make([]float64, h.w*h.h)
+	for y := 0; y < h.h; y++ {
+		for x := 0; x < h.w; x++ {
+			i := 2*y*g.w + 2*x
+			h.pix[y*h.w+x] = (g.pix[i] + g.pix[i+1] + g.pix[i+g.w] + g.pix[i+g.w+1]) / 4
+		}
+	}
+	return h
+}
+
+// integral 是灰度值及其平方的积分图，用于 O(1) 计算任意窗口的均值和方差
+type integral struct {
+	w          int
+	sum, sumSq []float64
+}
+
+func newIntegral(g *grayImage) *integral {
+	w := g.w + 1
+	in := &integral{w: w, sum: make([]float64, w*(g.h+1)), sumSq: make([]float64, w*(g.h+1))}
+	for y := 0; y < g.h; y++ {
+		var rowSum, rowSq float64
+		for x := 0; x < g.w; x++ {
+			v := g.pix[y*g.w+x]
+			rowSum += v
+			rowSq += v * v
+			in.sum[(y+1)*w+x+1] = in.sum[y*w+x+1] + rowSum
+			in.sumSq[(y+1)*w+x+1] = in.sumSq[y*w+x+1] + rowSq
+		}
+	}
+	return in
+}
+
+// window 返回以 (x, y) 为左上角、大小为 w x h 的窗口内的和与平方和
+func (in *integral) window(x, y, w, h int) (float64, float64) {
+	a, b := y*in.w+x, y*in.w+x+w
+	c, d := (y+h)*in.w+x, (y+h)*in.w+x+w
+	return in.sum[d] - in.sum[b] - in.sum[c] + in.sum[a],
+		in.sumSq[d] - in.sumSq[b] - in.sumSq[c] + in.sumSq[a]
+}
+
+// template 是预先去均值的模板
+type template struct {
+	w, h int
+	mean float64
+	norm float64 // 去均值后的平方和
+	pix  []float64
+}
+
+func newTemplate(g *grayImage) *template {
+	t := &template{w: g.w, h: g.h, pix: make([]float64, len(g.pix))}
+	for _, v := range g.pix {
+		t.mean += v
+	}
+	t.mean /= float64(len(g.pix))
+	for i, v := range g.pix {
+		t.pix[i] = v - t.mean
+		t.norm += t.pix[i] * t.pix[i]
+	}
+	return t
+}
+
+// score 计算模板与 img 中 (x, y) 处窗口的归一化互相关系数，结果截断到 [0, 1]
+func (t *template) score(img *grayImage, in *integral, x, y int) float64 {
+	n := float64(t.w * t.h)
+	sum, sumSq := in.window(x, y, t.w, t.h)
+	variance := sumSq - sum*sum/n
+
+	// 纯色模板或纯色区域无法计算相关系数，改为比较平均亮度
+	const eps = 1e-6
+	if t.norm < eps || variance < eps {
+		if t.norm < eps && variance < eps {
+			return 1 - math.Abs(sum/n-t.mean)/255
+		}
+		return 0
+	}
+
+	// 模板已去均值，窗口均值项相互抵消
+	var cross float64
+	for ty := 0; ty < t.h; ty++ {
+		row := img.pix[(y+ty)*img.w+x:]
+		tr := t.pix[ty*t.w : (ty+1)*t.w]
+		for tx, v := range tr {
+			cross += row[tx] * v
+		}
+	}
+
+	s := cross / math.Sqrt(variance*t.norm)
+	if s < 0 {
+		return 0
+	}
+	if s > 1 {
+		return 1
+	}
+	return s
+}
@@ -0,0 +1,99 @@
+package xcap
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math/rand"
+	"testing"
+)
+
+// noiseImage 生成确定性的随机纹理，避免模板在多个位置偶然相似
+func noiseImage(w, h int, seed int64) *image.RGBA {
+	rng := rand.New(rand.NewSource(seed))
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	// 以 4x4 的块填充，让缩小后的金字塔层仍保留纹理
+	for y := 0; y < h; y += 4 {
+		for x := 0; x < w; x += 4 {
+			v := uint8(rng.Intn(256))
+			fillRect(img, image.Rect(x, y, x+4, y+4), color.RGBA{v, v / 2, 255 - v, 255})
+		}
+	}
+	return img
+}
+
+func TestLocate(t *testing.T) {
+	haystack := noiseImage(400, 300, 1)
+	needle := noiseImage(48, 32, 2)
+
+	// 在两个位置放置模板，第二处整体变暗以验证对亮度变化不敏感
+	draw.Draw(haystack, image.Rect(50, 60, 98, 92), needle, image.Point{}, draw.Src)
+	dim := cropRGBA(needle, needle.Bounds())
+	for i := 0; i < len(dim.Pix); i += 4 {
+		dim.Pix[i+0] = dim.Pix[i+0] / 2
+		dim.Pix[i+1] = dim.Pix[i+1] / 2
+		dim.Pix[i+2] = dim.Pix[i+2] / 2
+	}
+	draw.Draw(haystack, image.Rect(301, 201, 349, 233), dim, image.Point{}, draw.Src)
+
+	matches, err := Locate(haystack, needle, LocateOptions{})
+	if err != nil {
+		t.Fatalf("Locate failed: %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("Locate returned %d matches, want 2: %v", len(matches), matches)
+	}
+
+	want := map[image.Rectangle]bool{
+		image.Rect(50, 60, 98, 92):     true,
+		image.Rect(301, 201, 349, 233): true,
+	}
+	for _, m := range matches {
+		if !want[m.Rect] {
+			t.Errorf("unexpected match %v (confidence %.3f)", m.Rect, m.Confidence)
+		}
+		if m.Confidence < 0.99 {
+			t.Errorf("match %v confidence = %.3f, want >= 0.99", m.Rect, m.Confidence)
+		}
+	}
+}
+
+func TestLocateNoMatch(t *testing.T) {
+	haystack := noiseImage(200, 200, 3)
+	needle := noiseImage(40, 40, 4)
+
+	matches, err := Locate(haystack, needle, LocateOptions{})
+	if err != nil {
+		t.Fatalf("Locate failed: %v", err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("Locate returned %v, want no matches", matches)
+	}
+
+	if matches, _ := Locate(needle, haystack, LocateOptions{}); len(matches) != 0 {
+		t.Errorf("needle larger than haystack returned %v", matches)
+	}
+	if _, err := Locate(haystack, image.NewRGBA(image.Rect(0, 0, 0, 0)), LocateOptions{}); err != ErrInvalidRegion {
+		t.Errorf("empty needle: err = %v, want ErrInvalidRegion", err)
+	}
+}
+
+func TestLocateOnMonitorGlobalCoordinates(t *testing.T) {
+	// 2x 缩放的显示器：截图为 800x600 像素，几何尺寸为 400x300 点
+	img := noiseImage(800, 600, 5)
+	needle := noiseImage(64, 48, 6)
+	draw.Draw(img, image.Rect(200, 100, 264, 148), needle, image.Point{}, draw.Src)
+
+	m := &fakeMonitor{x: -400, y: 0, width: 400, height: 300, scaleFactor: 2, img: img}
+
+	matches, err := LocateOnMonitor(m, needle, LocateOptions{})
+	if err != nil {
+		t.Fatalf("LocateOnMonitor failed: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("LocateOnMonitor returned %v, want one match", matches)
+	}
+	if want := image.Rect(-300, 50, -268, 74); matches[0].Rect != want {
+		t.Errorf("global rect = %v, want %v", matches[0].Rect, want)
+	}
+}
@@ -1,6 +1,10 @@
 package xcap
 
-import "image"
+import (
+	"context"
+	"image"
+	"image/color"
+)
 
 // Monitor 表示一个显示器/监视器
 type Monitor interface {
@@ -8,21 +12,24 @@ type Monitor interface {
 	ID() uint32
 
 	// Name 返回显示器的友好名称
+	// macOS 上名称需要主线程读取，主线程没有运行 main queue 时为 "Display <id>"
 	Name() string
 
-	// X 返回显示器左上角的 x 坐标
+	// X 返回显示器左上角的全局 x 坐标
+	// 几何方法使用 NativeSpace() 坐标空间：macOS 为点，Windows 为物理像素
 	X() int
 
-	// Y 返回显示器左上角的 y 坐标
+	// Y 返回显示器左上角的全局 y 坐标（NativeSpace() 空间）
 	Y() int
 
-	// Width 返回显示器的宽度（像素）
+	// Width 返回显示器的宽度（NativeSpace() 空间），截图宽度为 Width × ScaleFactor（macOS）或 Width（Windows）
 	Width() uint32
 
-	// Height 返回显示器的高度（像素）
+	// Height 返回显示器的高度（NativeSpace() 空间）
 	Height() uint32
 
-	// Rotation 返回旋转角度（0, 90, 180, 270）
+	// Rotation 返回画面相对帧缓冲的顺时针旋转角度（0, 90, 180, 270）
+	// 截图与 Width/Height 均为旋转后的可视方向，需要帧缓冲方向时使用 CaptureOptions.Orientation
 	Rotation() float32
 
 	// ScaleFactor 返回 DPI 缩放因子（如 Retina 显示器为 2.0）
@@ -37,9 +44,35 @@ type Monitor interface {
 	// IsBuiltin 返回是否为内置显示器（如笔记本屏幕）
 	IsBuiltin() bool
 
-	// CaptureImage 截取整个显示器，返回 RGBA 图像
+	// Info 返回显示器的硬件信息（厂商、型号、序列号、物理尺寸等），见 MonitorInfo
+	Info() (*MonitorInfo, error)
+
+	// StableID 返回在重启和重新连接后保持不变的标识符，可用于保存每个显示器的配置
+	//
+	// ID 由 EDID 厂商、产品代码和序列号组成，如 "DEL:A0BA:7MT0154S0ABL"，
+	// 与接口、位置无关，显示器换接口或调整排列后不变。
+	// 没有序列号或序列号是常见的占位值（如 "0000000000"）时改用连接方式和位置，
+	// 如 "DEL:A0BA:hdmi@1920,0"，此时只要接在同类接口上并保持排列位置，ID 就不变。
+	// 因此两台没有序列号的同型号显示器对调位置后 ID 也会对调。
+	//
+	// 冲突处理：其他情况下多台显示器得到相同 ID（固件写死了同一个非占位序列号）时，依次追加位置 "@x,y"；
+	// 位置仍相同（镜像显示）时按枚举顺序追加 "#1"、"#2"。
+	// 这两种后缀只在冲突的显示器同时连接时出现，另一台接入或断开时 ID 会变化。
+	//
+	// 计算冲突需要读取所有显示器的 Info，调用开销较大，应缓存结果；ID 格式应视为不透明
+	StableID() string
+
+	// CaptureImage 截取整个显示器，返回物理像素的 RGBA 图像
 	CaptureImage() (*image.RGBA, error)
 
-	// CaptureRegion 截取显示器的指定区域
+	// CaptureImageContext 与 CaptureImage 相同，但在 ctx 取消或超时时立即返回 ctx.Err()
+	// 被放弃的平台调用在工作线程上运行完毕后结果被丢弃，在此之前后续调用排队等待
+	CaptureImageContext(ctx context.Context) (*image.RGBA, error)
+
+	// CaptureRegion 截取显示器的指定区域，区域相对显示器左上角，与 Width/Height 同一坐标空间
 	CaptureRegion(x, y, width, height uint32) (*image.RGBA, error)
+
+	// PixelAt 返回显示器内坐标 (x, y) 处的像素颜色（sRGB）
+	// 坐标相对显示器左上角，与 Width/Height 同一坐标系
+	PixelAt(x, y int) (color.RGBA, error)
 }
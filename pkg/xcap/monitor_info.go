@@ -0,0 +1,148 @@
+package xcap
+
+import "strconv"
+
+// Connector 表示显示器的连接方式
+type Connector int
+
+const (
+	// ConnectorUnknown 连接方式未知
+	ConnectorUnknown Connector = iota
+	// ConnectorInternal 内置面板（笔记本屏幕、一体机）
+	ConnectorInternal
+	// ConnectorVGA 模拟 VGA（HD15）
+	ConnectorVGA
+	// ConnectorDVI DVI
+	ConnectorDVI
+	// ConnectorHDMI HDMI
+	ConnectorHDMI
+	// ConnectorDisplayPort 外接 DisplayPort（包括 USB-C/Thunderbolt 的 DP 模式）
+	ConnectorDisplayPort
+	// ConnectorWireless 无线投屏（如 Miracast）
+	ConnectorWireless
+	// ConnectorVirtual 虚拟或间接显示器（远程桌面、虚拟显示驱动）
+	ConnectorVirtual
+)
+
+// String 返回连接方式的名称
+func (c Connector) String() string {
+	switch c {
+	case ConnectorInternal:
+		return "internal"
+	case ConnectorVGA:
+		return "vga"
+	case ConnectorDVI:
+		return "dvi"
+	case ConnectorHDMI:
+		return "hdmi"
+	case ConnectorDisplayPort:
+		return "displayport"
+	case ConnectorWireless:
+		return "wireless"
+	case ConnectorVirtual:
+		return "virtual"
+	default:
+		return "unknown"
+	}
+}
+
+// MonitorInfo 描述显示器硬件，用于资产盘点
+//
+// 字段优先取自显示器的 EDID，EDID 不可用（如 Apple Silicon 上的 macOS、部分虚拟显示器）
+// 或缺少对应字段时使用系统 API 的值，两者都没有时为零值。
+type MonitorInfo struct {
+	// Manufacturer 三个字母的 PNP 厂商 ID，如 "DEL"
+	Manufacturer string
+
+	// ManufacturerName 厂商名称，如 "Dell"，不在内置表中时为空
+	ManufacturerName string
+
+	// Model 型号名称（EDID 显示器名称），没有时为 Monitor.Name()
+	Model string
+
+	// ProductCode 厂商定义的产品代码
+	ProductCode uint16
+
+	// SerialNumber 序列号，优先使用 EDID 序列号文本，其次为数字序列号
+	SerialNumber string
+
+	// Year 生产年份（或型号年份），未知时为 0
+	Year int
+
+	// WidthMM、HeightMM 显示区域的物理尺寸（毫米）
+	WidthMM  int
+	HeightMM int
+
+	// Connector 连接方式
+	Connector Connector
+
+	// ColorDepth 每个颜色通道的位数，如 8 或 10，未知时为 0
+	ColorDepth int
+
+	// EDID 原始 EDID 数据，不可用时为 nil
+	// 数据无法解析时仍然保留，其余字段来自系统 API
+	EDID []byte
+}
+
+// placeholderSerial 是不少显示器在没有序列号时填入 EDID 的占位值，按未提供处理
+const placeholderSerial = 0x01010101
+
+// nativeMonitorInfo 是平台层读取的显示器描述信息
+type nativeMonitorInfo struct {
+	name string
+	edid []byte
+
+	// 以下为系统 API 提供的值，EDID 缺少对应字段时使用
+	vendor       uint16 // PNP 厂商编码，与 EDID 第 8、9 字节相同
+	product      uint16
+	serial       uint32
+	widthMM      int
+	heightMM     int
+	connector    Connector
+	bitsPerColor int
+}
+
+// newMonitorInfo 合并 EDID 和系统 API 的信息
+// 系统 API 报告的连接方式比 EDID 声明的接口更准确，优先使用
+func newMonitorInfo(n nativeMonitorInfo) *MonitorInfo {
+	info := &MonitorInfo{
+		Manufacturer: pnpID(n.vendor),
+		Model:        n.name,
+		ProductCode:  n.product,
+		WidthMM:      n.widthMM,
+		HeightMM:     n.heightMM,
+		Connector:    n.connector,
+		ColorDepth:   n.bitsPerColor,
+		EDID:         n.edid,
+	}
+	if n.serial != 0 && n.serial != placeholderSerial {
+		info.SerialNumber = strconv.FormatUint(uint64(n.serial), 10)
+	}
+
+	if e, err := ParseEDID(n.edid); err == nil {
+		info.Manufacturer = e.ManufacturerID
+		info.ProductCode = e.ProductCode
+		info.Year = e.Year
+		if e.Name != "" {
+			info.Model = e.Name
+		}
+		switch {
+		case e.SerialString != "":
+			info.SerialNumber = e.SerialString
+		case e.SerialNumber != 0 && e.SerialNumber != placeholderSerial:
+			info.SerialNumber = strconv.FormatUint(uint64(e.SerialNumber), 10)
+		}
+		if e.WidthMM > 0 && e.HeightMM > 0 {
+			info.WidthMM, info.HeightMM = e.WidthMM, e.HeightMM
+		}
+		if info.Connector == ConnectorUnknown {
+			info.Connector = e.Interface
+		}
+		if e.BitsPerColor > 0 {
+			info.ColorDepth = e.BitsPerColor
+		}
+	}
+
+	info.ManufacturerName = pnpVendors[info.Manufacturer]
+	return info
+}
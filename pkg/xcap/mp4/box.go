@@ -0,0 +1,84 @@
+package mp4
+
+import "encoding/binary"
+
+// ISO BMFF box 构造辅助函数
+// 所有 box 都在内存中一次性构造完成，再整体写出
+
+// box 构造一个普通 box：size(4) + type(4) + payload
+func box(typ string, parts ...[]byte) []byte {
+	size := 8
+	for _, p := range parts {
+		size += len(p)
+	}
+
+	b := make([]byte, 8, size)
+	binary.BigEndian.PutUint32(b[0:4], uint32(size))
+	copy(b[4:8], typ)
+	for _, p := range parts {
+		b = append(b, p...)
+	}
+	return b
+}
+
+// fullBox 构造一个 full box：在普通 box 的基础上增加 version(1) + flags(3)
+func fullBox(typ string, version uint8, flags uint32, parts ...[]byte) []byte {
+	header := u32(uint32(version)<<24 | flags&0x00FFFFFF)
+	return box(typ, append([][]byte{header}, parts...)...)
+}
+
+func u8(v uint8) []byte {
+	return []byte{v}
+}
+
+func u16(v uint16) []byte {
+	b := make([]byte, 2)
+	binary.BigEndian.PutUint16(b, v)
+	return b
+}
+
+func u32(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, v)
+	return b
+}
+
+func u64(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	return b
+}
+
+func zeros(n int) []byte {
+	return make([]byte, n)
+}
+
+// unityMatrix 是 mvhd/tkhd 中使用的单位变换矩阵
+func unityMatrix() []byte {
+	var b []byte
+	for _, v := range []uint32{0x00010000, 0, 0, 0, 0x00010000, 0, 0, 0, 0x40000000} {
+		b = append(b, u32(v)...)
+	}
+	return b
+}
+
+// descriptor 构造 MPEG-4 Systems 描述符（用于 esds）
+// 长度字段使用 4 字节的扩展编码，兼容性最好
+func descriptor(tag uint8, payload ...[]byte) []byte {
+	size := 0
+	for _, p := range payload {
+		size += len(p)
+	}
+
+	b := []byte{
+		tag,
+		0x80 | byte(size>>21&0x7F),
+		0x80 | byte(size>>14&0x7F),
+		0x80 | byte(size>>7&0x7F),
+		byte(size & 0x7F),
+	}
+	for _, p := range payload {
+		b = append(b, p...)
+	}
+	return b
+}
@@ -0,0 +1,167 @@
+package mp4
+
+// 轨道相关常量
+const (
+	trackID        = 1
+	movieTimescale = 1000
+
+	// mp4ObjectTypeJPEG 是 MPEG-4 Systems 中 JPEG 的 objectTypeIndication
+	mp4ObjectTypeJPEG = 0x6C
+
+	// sampleFlagsSync 表示不依赖其他样本的同步样本（关键帧）
+	sampleFlagsSync = 0x02000000
+
+	// tfhd/trun 标志位
+	tfhdDefaultBaseIsMoof  = 0x020000
+	trunDataOffsetPresent  = 0x000001
+	trunDurationPresent    = 0x000100
+	trunSizePresent        = 0x000200
+	trunSampleFlagsPresent = 0x000400
+)
+
+// ftyp 构造文件类型 box
+func (m *Muxer) ftyp() []byte {
+	return box("ftyp",
+		[]byte("isom"), u32(0x200),
+		[]byte("isom"), []byte("iso6"), []byte("mp41"),
+	)
+}
+
+// moov 构造不含样本的 movie box，样本全部位于后续片段中
+func (m *Muxer) moov() []byte {
+	mvhd := fullBox("mvhd", 0, 0,
+		u32(0), u32(0), // creation / modification time
+		u32(movieTimescale),
+		u32(0),          // duration 未知
+		u32(0x00010000), // rate 1.0
+		u16(0x0100),     // volume 1.0
+		zeros(2+8),
+		unityMatrix(),
+		zeros(24),
+		u32(trackID+1), // next_track_ID
+	)
+
+	mvex := box("mvex",
+		fullBox("trex", 0, 0,
+			u32(trackID),
+			u32(1), // default_sample_description_index
+			u32(0), // default_sample_duration
+			u32(0), // default_sample_size
+			u32(0), // default_sample_flags
+		),
+	)
+
+	return box("moov", mvhd, m.trak(), mvex)
+}
+
+// trak 构造视频轨道
+func (m *Muxer) trak() []byte {
+	w, h := uint32(m.cfg.Width), uint32(m.cfg.Height)
+
+	tkhd := fullBox("tkhd", 0, 0x000003, // track_enabled | track_in_movie
+		u32(0), u32(0), // creation / modification time
+		u32(trackID),
+		zeros(4),
+		u32(0), // duration 未知
+		zeros(8),
+		u16(0), u16(0), // layer / alternate_group
+		u16(0), // volume
+		zeros(2),
+		unityMatrix(),
+		u32(w<<16), u32(h<<16),
+	)
+
+	mdhd := fullBox("mdhd", 0, 0,
+		u32(0), u32(0),
+		u32(m.cfg.Timescale),
+		u32(0),
+		u16(0x55C4), // language: und
+		u16(0),
+	)
+
+	hdlr := fullBox("hdlr", 0, 0,
+		u32(0),
+		[]byte("vide"),
+		zeros(12),
+		[]byte("VideoHandler\x00"),
+	)
+
+	dinf := box("dinf",
+		fullBox("dref", 0, 0, u32(1), fullBox("url ", 0, 0x000001)),
+	)
+
+	stbl := box("stbl",
+		fullBox("stsd", 0, 0, u32(1), m.sampleEntry()),
+		fullBox("stts", 0, 0, u32(0)),
+		fullBox("stsc", 0, 0, u32(0)),
+		fullBox("stsz", 0, 0, u32(0), u32(0)),
+		fullBox("stco", 0, 0, u32(0)),
+	)
+
+	minf := box("minf",
+		fullBox("vmhd", 0, 0x000001, zeros(8)),
+		dinf,
+		stbl,
+	)
+
+	return box("trak", tkhd, box("mdia", mdhd, hdlr, minf))
+}
+
+// sampleEntry 构造视频样本描述
+// MJPEG 使用 mp4v + esds（objectTypeIndication 0x6C），未压缩帧使用 QuickTime 的 'raw ' 24 位 RGB
+func (m *Muxer) sampleEntry() []byte {
+	typ, name := "mp4v", "MJPEG"
+	var extra [][]byte
+
+	if m.cfg.Codec == CodecRaw {
+		typ, name = "raw ", "Uncompressed RGB"
+	} else {
+		extra = append(extra, fullBox("esds", 0, 0,
+			descriptor(0x03, // ES_Descriptor
+				u16(1), u8(0),
+				descriptor(0x04, // DecoderConfigDescriptor
+					u8(mp4ObjectTypeJPEG),
+					u8(0x04<<2|0x01), // streamType: visual
+					zeros(3),         // bufferSizeDB
+					u32(0), u32(0),   // max / avg bitrate
+				),
+				descriptor(0x06, u8(0x02)), // SLConfigDescriptor
+			),
+		))
+	}
+
+	compressor := make([]byte, 32)
+	compressor[0] = byte(len(name))
+	copy(compressor[1:], name)
+
+	parts := [][]byte{
+		zeros(6), u16(1), // reserved / data_reference_index
+		zeros(16),
+		u16(uint16(m.cfg.Width)), u16(uint16(m.cfg.Height)),
+		u32(0x00480000), u32(0x00480000), // 72 dpi
+		zeros(4),
+		u16(1), // frame_count
+		compressor,
+		u16(0x0018), // depth
+		u16(0xFFFF), // pre_defined = -1
+	}
+	return box(typ, append(parts, extra...)...)
+}
+
+// moof 构造只包含一个样本的片段头
+func (m *Muxer) moof(s *sample, duration uint32, dataOffset int32) []byte {
+	mfhd := fullBox("mfhd", 0, 0, u32(m.sequence))
+
+	tfhd := fullBox("tfhd", 0, tfhdDefaultBaseIsMoof, u32(trackID))
+	tfdt := fullBox("tfdt", 1, 0, u64(s.dts))
+	trun := fullBox("trun", 0,
+		trunDataOffsetPresent|trunDurationPresent|trunSizePresent|trunSampleFlagsPresent,
+		u32(1),
+		u32(uint32(dataOffset)),
+		u32(duration),
+		u32(uint32(len(s.data))),
+		u32(sampleFlagsSync),
+	)
+
+	return box("moof", mfhd, box("traf", tfhd, tfdt, trun))
+}
@@ -0,0 +1,273 @@
+// Package mp4 提供纯 Go 实现的 fragmented MP4（ISO BMFF）封装器，
+// 用于把一次截图会话打包为边写边可读的视频文件。
+//
+// MJPEG 轨道使用 mp4v + esds（objectTypeIndication 0x6C），未压缩轨道使用 'raw '
+// 样本描述。浏览器不支持这两种编码，输出文件需要用 ffmpeg、VLC 等播放，
+// 或用 ffmpeg 转码为 H.264 后再在浏览器中播放。
+//
+// 文件结构为 ftyp + moov（不含样本表，带 mvex），随后每个关键帧
+// 开始一个新的 moof + mdat 片段。MJPEG 和未压缩帧都是关键帧，
+// 因此每一帧对应一个片段。
+//
+// 帧时长取自相邻两帧的采集时间戳，所以每一帧都要等到下一帧到达
+// 后才能写出；进程崩溃时最多丢失最后一个尚未写出的片段。
+//
+// 基本用法：
+//
+//	f, _ := os.Create("session.mp4")
+//	mux, err := mp4.NewMuxer(f, mp4.Config{
+//	    Codec:  mp4.CodecMJPEG,
+//	    Width:  img.Bounds().Dx(),
+//	    Height: img.Bounds().Dy(),
+//	})
+//	for {
+//	    img, _ := monitor.CaptureImage()
+//	    mux.WriteImage(img, time.Now())
+//	}
+//	mux.Close()
+package mp4
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"io"
+	"math"
+	"time"
+)
+
+// Codec 表示视频轨道的编码格式
+type Codec int
+
+const (
+	// CodecMJPEG 每帧为一张独立的 JPEG 图像
+	CodecMJPEG Codec = iota
+
+	// CodecRaw 每帧为未压缩的 24 位 RGB 数据（逐行紧密排列，无填充）
+	CodecRaw
+)
+
+// 默认参数
+const (
+	// DefaultTimescale 默认的媒体时间刻度（每秒 tick 数）
+	DefaultTimescale = 90000
+
+	// DefaultJPEGQuality 默认的 JPEG 编码质量
+	DefaultJPEGQuality = 85
+
+	// defaultFrameRate 在只有一帧时用于推算最后一帧的时长
+	defaultFrameRate = 30
+)
+
+// 封装错误定义
+var (
+	// ErrInvalidConfig 在配置无效时返回
+	ErrInvalidConfig = errors.New("mp4: invalid config")
+
+	// ErrFrameSize 在帧尺寸或数据长度与轨道不符时返回
+	ErrFrameSize = errors.New("mp4: frame size does not match track")
+
+	// ErrTimestampOrder 在帧时间戳早于上一帧时返回
+	ErrTimestampOrder = errors.New("mp4: frame timestamp is before previous frame")
+
+	// ErrClosed 在封装器关闭后继续写入时返回
+	ErrClosed = errors.New("mp4: muxer is closed")
+)
+
+// Config 描述输出文件中唯一的视频轨道
+type Config struct {
+	// Codec 视频编码格式
+	Codec Codec
+
+	// Width, Height 帧尺寸（像素）
+	Width  int
+	Height int
+
+	// Timescale 媒体时间刻度，为 0 时使用 DefaultTimescale
+	Timescale uint32
+
+	// JPEGQuality WriteImage 在 MJPEG 轨道上使用的编码质量（1-100），为 0 时使用 DefaultJPEGQuality
+	JPEGQuality int
+}
+
+// sample 表示一个已编码、等待写出的帧
+type sample struct {
+	data []byte
+	dts  uint64
+}
+
+// Muxer 将帧序列写为 fragmented MP4
+// Muxer 不是并发安全的，同一时间只能由一个 goroutine 写入
+type Muxer struct {
+	w   io.Writer
+	cfg Config
+
+	start    time.Time
+	pending  *sample
+	lastDur  uint32
+	sequence uint32
+	closed   bool
+}
+
+// NewMuxer 创建封装器并立即写出文件头（ftyp + moov）
+func NewMuxer(w io.Writer, cfg Config) (*Muxer, error) {
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width > 0xFFFF || cfg.Height > 0xFFFF {
+		return nil, ErrInvalidConfig
+	}
+	if cfg.Codec != CodecMJPEG && cfg.Codec != CodecRaw {
+		return nil, ErrInvalidConfig
+	}
+	if cfg.Timescale == 0 {
+		cfg.Timescale = DefaultTimescale
+	}
+	if cfg.JPEGQuality == 0 {
+		cfg.JPEGQuality = DefaultJPEGQuality
+	}
+
+	m := &Muxer{w: w, cfg: cfg}
+
+	header := append(m.ftyp(), m.moov()...)
+	if err := m.write(header); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// WriteImage 按轨道编码格式编码图像并写入
+// 图像尺寸必须与 Config 中的尺寸一致
+func (m *Muxer) WriteImage(img *image.RGBA, ts time.Time) error {
+	b := img.Bounds()
+	if b.Dx() != m.cfg.Width || b.Dy() != m.cfg.Height {
+		return ErrFrameSize
+	}
+
+	switch m.cfg.Codec {
+	case CodecMJPEG:
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: m.cfg.JPEGQuality}); err != nil {
+			return err
+		}
+		return m.WriteFrame(buf.Bytes(), ts)
+	default:
+		return m.WriteFrame(packRGB(img), ts)
+	}
+}
+
+// WriteFrame 写入一帧已编码的数据
+// MJPEG 轨道传入完整的 JPEG 数据，Raw 轨道传入 Width*Height*3 字节的 RGB 数据
+// 该帧在下一帧到达（或 Close）时才会真正写出
+func (m *Muxer) WriteFrame(data []byte, ts time.Time) error {
+	if m.closed {
+		return ErrClosed
+	}
+	if m.cfg.Codec == CodecRaw && len(data) != m.cfg.Width*m.cfg.Height*3 {
+		return ErrFrameSize
+	}
+
+	if m.pending == nil && m.sequence == 0 {
+		m.start = ts
+	}
+
+	d := ts.Sub(m.start)
+	if d < 0 {
+		return ErrTimestampOrder
+	}
+	dts := toTicks(d, m.cfg.Timescale)
+
+	if m.pending != nil {
+		if dts < m.pending.dts {
+			return ErrTimestampOrder
+		}
+		// 同一 tick 内的两帧仍需要至少 1 tick 的时长
+		if dts == m.pending.dts {
+			dts++
+		}
+		// trun 中的样本时长只有 32 位（90kHz 下约 13 小时），更长的间隔截断为最大值；
+		// 下一个片段的 tfdt 记录的是绝对时间，时间轴不会因此偏移
+		dur := dts - m.pending.dts
+		if dur > math.MaxUint32 {
+			dur = math.MaxUint32
+		}
+		if err := m.flush(uint32(dur)); err != nil {
+			return err
+		}
+	}
+
+	m.pending = &sample{data: append([]byte(nil), data...), dts: dts}
+	return nil
+}
+
+// Close 写出最后一帧
+// 最后一帧的时长沿用上一帧的时长；只有一帧时按 30fps 计算
+// Close 不会关闭底层的 io.Writer
+func (m *Muxer) Close() error {
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+
+	if m.pending == nil {
+		return nil
+	}
+
+	dur := m.lastDur
+	if dur == 0 {
+		dur = m.cfg.Timescale / defaultFrameRate
+	}
+	return m.flush(dur)
+}
+
+// flush 将等待中的帧作为一个独立片段写出
+func (m *Muxer) flush(duration uint32) error {
+	s := m.pending
+	m.pending = nil
+	m.sequence++
+	m.lastDur = duration
+
+	// 先以 0 作为 data_offset 构造 moof 得到其长度，再用真实偏移重建
+	moof := m.moof(s, duration, 0)
+	moof = m.moof(s, duration, int32(len(moof)+8))
+
+	fragment := append(moof, box("mdat", s.data)...)
+	return m.write(fragment)
+}
+
+// write 一次性写出数据，并在底层 Writer 支持时立即落盘
+func (m *Muxer) write(b []byte) error {
+	if _, err := m.w.Write(b); err != nil {
+		return err
+	}
+	if f, ok := m.w.(interface{ Flush() error }); ok {
+		if err := f.Flush(); err != nil {
+			return err
+		}
+	}
+	if f, ok := m.w.(interface{ Sync() error }); ok {
+		if err := f.Sync(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// toTicks 将时长换算为媒体时间刻度，分两段计算以避免长会话溢出
+func toTicks(d time.Duration, timescale uint32) uint64 {
+	sec := uint64(d / time.Second)
+	rem := uint64(d % time.Second)
+	return sec*uint64(timescale) + rem*uint64(timescale)/uint64(time.Second)
+}
+
+// packRGB 将 RGBA 图像转换为紧密排列的 24 位 RGB 数据
+func packRGB(img *image.RGBA) []byte {
+	b := img.Bounds()
+	out := make([]byte, 0, b.Dx()*b.Dy()*3)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		row := img.Pix[img.PixOffset(b.Min.X, y):img.PixOffset(b.Max.X, y)]
+		for x := 0; x < len(row); x += 4 {
+			out = append(out, row[x], row[x+1], row[x+2])
+		}
+	}
+	return out
+}
@@ -0,0 +1,221 @@
+package mp4
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+	"time"
+)
+
+// parsedBox 是测试中使用的简易 box 表示
+type parsedBox struct {
+	typ     string
+	payload []byte
+}
+
+// parseBoxes 解析一层 box 序列
+func parseBoxes(t *testing.T, data []byte) []parsedBox {
+	t.Helper()
+
+	var boxes []parsedBox
+	for len(data) > 0 {
+		if len(data) < 8 {
+			t.Fatalf("truncated box header: %d bytes left", len(data))
+		}
+		size := int(binary.BigEndian.Uint32(data[0:4]))
+		if size < 8 || size > len(data) {
+			t.Fatalf("invalid box size %d (have %d bytes)", size, len(data))
+		}
+		boxes = append(boxes, parsedBox{typ: string(data[4:8]), payload: data[8:size]})
+		data = data[size:]
+	}
+	return boxes
+}
+
+// findBox 按路径查找子 box，full box 的 version/flags 需调用方自行跳过
+func findBox(t *testing.T, data []byte, path ...string) []byte {
+	t.Helper()
+
+	for _, name := range path {
+		found := false
+		for _, b := range parseBoxes(t, data) {
+			if b.typ == name {
+				data = b.payload
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Fatalf("box %q not found in path %v", name, path)
+		}
+	}
+	return data
+}
+
+func solidImage(w, h int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i+0] = c.R
+		img.Pix[i+1] = c.G
+		img.Pix[i+2] = c.B
+		img.Pix[i+3] = c.A
+	}
+	return img
+}
+
+func TestMuxerLayout(t *testing.T) {
+	var buf bytes.Buffer
+	mux, err := NewMuxer(&buf, Config{Codec: CodecMJPEG, Width: 16, Height: 8})
+	if err != nil {
+		t.Fatalf("NewMuxer failed: %v", err)
+	}
+
+	start := time.Unix(1700000000, 0)
+	offsets := []time.Duration{0, 100 * time.Millisecond, 250 * time.Millisecond}
+	for _, off := range offsets {
+		if err := mux.WriteImage(solidImage(16, 8, color.RGBA{255, 0, 0, 255}), start.Add(off)); err != nil {
+			t.Fatalf("WriteImage failed: %v", err)
+		}
+	}
+	if err := mux.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	var types []string
+	for _, b := range parseBoxes(t, buf.Bytes()) {
+		types = append(types, b.typ)
+	}
+	want := []string{"ftyp", "moov", "moof", "mdat", "moof", "mdat", "moof", "mdat"}
+	if len(types) != len(want) {
+		t.Fatalf("box layout = %v, want %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Fatalf("box layout = %v, want %v", types, want)
+		}
+	}
+
+	entry := findBox(t, buf.Bytes(), "moov", "trak", "mdia", "minf", "stbl", "stsd")
+	if got := string(entry[12:16]); got != "mp4v" {
+		t.Errorf("sample entry = %q, want mp4v", got)
+	}
+}
+
+func TestMuxerTiming(t *testing.T) {
+	var buf bytes.Buffer
+	mux, err := NewMuxer(&buf, Config{Codec: CodecRaw, Width: 2, Height: 2, Timescale: 1000})
+	if err != nil {
+		t.Fatalf("NewMuxer failed: %v", err)
+	}
+
+	start := time.Unix(1700000000, 0)
+	offsets := []time.Duration{0, 40 * time.Millisecond, 140 * time.Millisecond}
+	for _, off := range offsets {
+		if err := mux.WriteFrame(make([]byte, 2*2*3), start.Add(off)); err != nil {
+			t.Fatalf("WriteFrame failed: %v", err)
+		}
+	}
+	if err := mux.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	var fragments [][]byte
+	boxes := parseBoxes(t, buf.Bytes())
+	for i, b := range boxes {
+		if b.typ == "moof" {
+			// 重建完整 box 以便 findBox 解析
+			fragments = append(fragments, box("moof", b.payload))
+			if boxes[i+1].typ != "mdat" || len(boxes[i+1].payload) != 12 {
+				t.Fatalf("fragment %d: unexpected mdat", len(fragments))
+			}
+		}
+	}
+
+	wantDTS := []uint64{0, 40, 140}
+	wantDur := []uint32{40, 100, 100}
+	for i, frag := range fragments {
+		tfdt := findBox(t, frag, "moof", "traf", "tfdt")
+		if dts := binary.BigEndian.Uint64(tfdt[4:12]); dts != wantDTS[i] {
+			t.Errorf("fragment %d: dts = %d, want %d", i, dts, wantDTS[i])
+		}
+
+		trun := findBox(t, frag, "moof", "traf", "trun")
+		offset := binary.BigEndian.Uint32(trun[8:12])
+		if int(offset) != len(frag)+8 {
+			t.Errorf("fragment %d: data_offset = %d, want %d", i, offset, len(frag)+8)
+		}
+		if dur := binary.BigEndian.Uint32(trun[12:16]); dur != wantDur[i] {
+			t.Errorf("fragment %d: duration = %d, want %d", i, dur, wantDur[i])
+		}
+	}
+}
+
+func TestMuxerLongGap(t *testing.T) {
+	var buf bytes.Buffer
+	mux, err := NewMuxer(&buf, Config{Codec: CodecRaw, Width: 2, Height: 2})
+	if err != nil {
+		t.Fatalf("NewMuxer failed: %v", err)
+	}
+
+	// 90kHz 下超过 32 位样本时长的间隔
+	start := time.Unix(1700000000, 0)
+	gap := 14 * time.Hour
+	for _, ts := range []time.Time{start, start.Add(gap)} {
+		if err := mux.WriteFrame(make([]byte, 2*2*3), ts); err != nil {
+			t.Fatalf("WriteFrame failed: %v", err)
+		}
+	}
+	if err := mux.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	var fragments [][]byte
+	for _, b := range parseBoxes(t, buf.Bytes()) {
+		if b.typ == "moof" {
+			fragments = append(fragments, box("moof", b.payload))
+		}
+	}
+	if len(fragments) != 2 {
+		t.Fatalf("got %d fragments, want 2", len(fragments))
+	}
+
+	trun := findBox(t, fragments[0], "moof", "traf", "trun")
+	if dur := binary.BigEndian.Uint32(trun[12:16]); dur != math.MaxUint32 {
+		t.Errorf("duration = %d, want clamped to %d", dur, uint32(math.MaxUint32))
+	}
+	tfdt := findBox(t, fragments[1], "moof", "traf", "tfdt")
+	if dts, want := binary.BigEndian.Uint64(tfdt[4:12]), uint64(gap/time.Second)*DefaultTimescale; dts != want {
+		t.Errorf("second fragment dts = %d, want %d", dts, want)
+	}
+}
+
+func TestMuxerErrors(t *testing.T) {
+	if _, err := NewMuxer(&bytes.Buffer{}, Config{Codec: CodecRaw}); err != ErrInvalidConfig {
+		t.Errorf("NewMuxer with zero size: err = %v, want ErrInvalidConfig", err)
+	}
+
+	mux, err := NewMuxer(&bytes.Buffer{}, Config{Codec: CodecRaw, Width: 2, Height: 2})
+	if err != nil {
+		t.Fatalf("NewMuxer failed: %v", err)
+	}
+
+	if err := mux.WriteFrame(make([]byte, 5), time.Now()); err != ErrFrameSize {
+		t.Errorf("short frame: err = %v, want ErrFrameSize", err)
+	}
+
+	now := time.Now()
+	if err := mux.WriteFrame(make([]byte, 12), now); err != nil {
+		t.Fatalf("WriteFrame failed: %v", err)
+	}
+	if err := mux.WriteFrame(make([]byte, 12), now.Add(-time.Second)); err != ErrTimestampOrder {
+		t.Errorf("out of order frame: err = %v, want ErrTimestampOrder", err)
+	}
+
+	mux.Close()
+	if err := mux.WriteFrame(make([]byte, 12), now.Add(time.Second)); err != ErrClosed {
+		t.Errorf("write after close: err = %v, want ErrClosed", err)
+	}
+}
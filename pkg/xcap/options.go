@@ -0,0 +1,116 @@
+package xcap
+
+import (
+	"image"
+	"math"
+)
+
+// CaptureOptions 控制 CaptureMonitorWithOptions/CaptureWindowWithOptions/CaptureDesktopWithOptions 的行为
+// 零值与直接调用 CaptureImage 相同
+type CaptureOptions struct {
+	// RejectBlankFrames 为 true 时，空白帧（见 FrameQuality.IsBlank）返回 ErrBlankFrame 而不是图像
+	// 用于避免把缺少权限、显示器休眠等情况下得到的纯黑图像当作有效截图保存
+	RejectBlankFrames bool
+
+	// IncludeCursor 为 true 时把鼠标指针按截图的缩放比例绘制到显示器和桌面截图上
+	// 系统截图本身不包含指针；窗口截图不受影响
+	IncludeCursor bool
+
+	// Scale 输出图像相对截图像素尺寸的缩放比例，为 0 时不缩放
+	// 例如在 2x 屏幕上设为 1/ScaleFactor() 得到逻辑分辨率的截图
+	Scale float64
+
+	// MaxWidth、MaxHeight 输出图像的最大尺寸（像素），为 0 时不限制
+	// 超出时保持宽高比缩小，不会放大
+	MaxWidth  int
+	MaxHeight int
+
+	// Filter 缩放时使用的重采样滤波器，零值为 ResampleLanczos
+	// 需要缩放而 Filter 不是已定义的滤波器时，截图返回 ErrInvalidFilter
+	Filter ResampleFilter
+
+	// Orientation 显示器截图的输出方向，零值为 OrientationVisual
+	// OrientationFramebuffer 按 Monitor.Rotation 把画面转回帧缓冲方向；窗口和桌面截图不受影响
+	Orientation Orientation
+
+	// WindowArea 窗口截图覆盖的区域，零值为 WindowAreaFrame；显示器和桌面截图不受影响
+	WindowArea WindowArea
+}
+
+// CaptureMonitorWithOptions 按 opts 截取整个显示器
+func CaptureMonitorWithOptions(m Monitor, opts CaptureOptions) (*image.RGBA, error) {
+	img, err := m.CaptureImage()
+	if err != nil {
+		return nil, err
+	}
+	return opts.finish("capture monitor", m.ID(), img, monitorRect(m), m.Rotation())
+}
+
+// CaptureWindowWithOptions 按 opts 截取窗口
+func CaptureWindowWithOptions(w Window, opts CaptureOptions) (*image.RGBA, error) {
+	img, err := captureWindowArea(w, opts.WindowArea)
+	if err != nil {
+		return nil, err
+	}
+	return opts.finish("capture window", w.ID(), img, image.Rectangle{}, 0)
+}
+
+// CaptureDesktopWithOptions 按 opts 截取整个虚拟桌面，见 CaptureDesktop
+func CaptureDesktopWithOptions(opts CaptureOptions) (*image.RGBA, error) {
+	monitors, err := AllMonitors()
+	if err != nil {
+		return nil, err
+	}
+
+	img, area, err := captureDesktop(monitors)
+	if err != nil {
+		return nil, err
+	}
+	return opts.finish("capture desktop", 0, img, area, 0)
+}
+
+// finish 对截图结果应用 opts 中的检查和后处理
+// area 为 img 覆盖的全局坐标区域，窗口截图传入空区域，不绘制指针
+// rotation 为显示器的 Monitor.Rotation，窗口和桌面截图传入 0
+func (opts CaptureOptions) finish(op string, id uint32, img *image.RGBA, area image.Rectangle, rotation float32) (*image.RGBA, error) {
+	// 先检查原始帧，避免指针让黑屏看起来有内容
+	if opts.RejectBlankFrames {
+		if q := AnalyzeFrame(img); q.IsBlank() {
+			return nil, &Error{Op: op, ID: id, Kind: ErrBlankFrame, Err: &BlankFrameError{Quality: q}}
+		}
+	}
+
+	if opts.IncludeCursor && !area.Empty() {
+		if err := overlayCursor(img, area); err != nil {
+			return nil, err
+		}
+	}
+
+	// 指针按可视方向绘制，之后再转换方向
+	if opts.Orientation == OrientationFramebuffer {
+		img = toFramebuffer(img, rotation)
+	}
+
+	if opts.Scale > 0 || opts.MaxWidth > 0 || opts.MaxHeight > 0 {
+		if w, h := opts.outputSize(img.Bounds().Size()); w != img.Bounds().Dx() || h != img.Bounds().Dy() {
+			return Resize(img, w, h, opts.Filter)
+		}
+	}
+
+	return img, nil
+}
+
+// outputSize 根据 Scale、MaxWidth、MaxHeight 计算输出图像的尺寸，结果至少为 1x1
+func (opts CaptureOptions) outputSize(size image.Point) (int, int) {
+	w, h := float64(size.X), float64(size.Y)
+	if opts.Scale > 0 {
+		w, h = w*opts.Scale, h*opts.Scale
+	}
+	if opts.MaxWidth > 0 && w > float64(opts.MaxWidth) {
+		w, h = float64(opts.MaxWidth), h*float64(opts.MaxWidth)/w
+	}
+	if opts.MaxHeight > 0 && h > float64(opts.MaxHeight) {
+		w, h = w*float64(opts.MaxHeight)/h, float64(opts.MaxHeight)
+	}
+	return max(int(math.Round(w)), 1), max(int(math.Round(h)), 1)
+}
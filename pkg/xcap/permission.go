@@ -0,0 +1,44 @@
+package xcap
+
+// PermissionStatus 表示截图权限的状态
+type PermissionStatus int
+
+const (
+	// PermissionUnknown 无法确定是否有权限
+	PermissionUnknown PermissionStatus = iota
+
+	// PermissionGranted 已获得截图权限
+	PermissionGranted
+
+	// PermissionDenied 缺少截图权限，截图会返回 ErrPermissionDenied
+	PermissionDenied
+)
+
+// String 返回权限状态的名称
+func (s PermissionStatus) String() string {
+	switch s {
+	case PermissionGranted:
+		return "granted"
+	case PermissionDenied:
+		return "denied"
+	}
+	return "unknown"
+}
+
+// CheckPermission 返回当前进程的截图权限状态，不会弹出系统提示
+//
+// macOS 上检查 Screen Recording 权限；用户在系统设置中授权后，
+// 通常需要重启进程才会返回 PermissionGranted。
+// Windows 不需要额外权限，总是返回 PermissionGranted。
+func CheckPermission() PermissionStatus {
+	return platform.checkPermission()
+}
+
+// RequestPermission 请求截图权限并返回请求后的状态
+//
+// macOS 上首次调用会弹出系统授权提示，之后的调用只返回当前状态；
+// 用户需要在「系统设置 > 隐私与安全性 > 屏幕录制」中手动修改。
+// 其他平台等同于 CheckPermission。
+func RequestPermission() PermissionStatus {
+	return platform.requestPermission()
+}
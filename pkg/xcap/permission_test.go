@@ -0,0 +1,24 @@
+package xcap
+
+import "testing"
+
+func TestPermission(t *testing.T) {
+	b := &fakeBackend{permission: PermissionDenied}
+	useFakeBackend(t, b)
+
+	if got := CheckPermission(); got != PermissionDenied {
+		t.Errorf("CheckPermission() = %v, want denied", got)
+	}
+	if b.requested {
+		t.Error("CheckPermission must not request permission")
+	}
+
+	b.permission = PermissionGranted
+	if got := RequestPermission(); got != PermissionGranted || !b.requested {
+		t.Errorf("RequestPermission() = %v (requested %v), want granted", got, b.requested)
+	}
+
+	if got := PermissionStatus(42).String(); got != "unknown" {
+		t.Errorf("String() = %q, want unknown", got)
+	}
+}
@@ -0,0 +1,183 @@
+package xcap
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+)
+
+// 帧质量检测参数
+const (
+	// NearBlackThreshold 各通道都不超过该值的像素视为近黑
+	NearBlackThreshold = 16
+
+	// BlankBlackRatio 近黑像素占比达到该值时视为空白帧
+	BlankBlackRatio = 0.99
+
+	// uniformTolerance 纯色判断时每个通道允许的最大差值，用于容忍压缩和抖动产生的噪声
+	uniformTolerance = 2
+
+	// protectedCellSize 检测纯黑矩形时的分块边长
+	protectedCellSize = 8
+
+	// protectedMinSize 疑似受保护矩形的最小边长，避免把图标、文字等小面积黑色内容当作受保护区域
+	protectedMinSize = 64
+)
+
+// FrameQuality 描述一帧截图的内容，用于发现“截图成功但内容无效”的情况
+//
+// 受 DRM 保护的视频、缺少权限、显示器休眠等情况下，系统通常不会报错，
+// 而是返回纯黑或纯色的图像，或者只把受保护的区域涂黑。
+type FrameQuality struct {
+	// Uniform 所有像素的颜色是否相同（每个通道允许 uniformTolerance 的差值）
+	Uniform bool
+
+	// Color Uniform 为 true 时为该颜色，否则为整帧的平均颜色
+	Color color.RGBA
+
+	// BlackRatio 近黑像素（见 NearBlackThreshold）占全部像素的比例
+	BlackRatio float64
+
+	// ProtectedRects 疑似被系统涂黑的受保护内容区域（图像坐标）
+	// 即帧内大块的纯黑矩形；整帧空白时为空
+	ProtectedRects []image.Rectangle
+}
+
+// IsBlank 返回该帧是否为空白帧：纯色，或几乎全部为近黑像素
+func (q *FrameQuality) IsBlank() bool {
+	return q.Uniform || q.BlackRatio >= BlankBlackRatio
+}
+
+// String 返回帧质量的简要描述
+func (q *FrameQuality) String() string {
+	switch {
+	case q.Uniform:
+		return fmt.Sprintf("uniform color #%02x%02x%02x", q.Color.R, q.Color.G, q.Color.B)
+	case q.BlackRatio >= BlankBlackRatio:
+		return fmt.Sprintf("%.1f%% near-black pixels", q.BlackRatio*100)
+	case len(q.ProtectedRects) > 0:
+		return fmt.Sprintf("%d suspected protected region(s)", len(q.ProtectedRects))
+	}
+	return "ok"
+}
+
+// AnalyzeFrame 检查 img 的内容，返回帧质量
+// 空图像视为纯色帧
+func AnalyzeFrame(img *image.RGBA) *FrameQuality {
+	b := img.Bounds()
+	if b.Empty() {
+		return &FrameQuality{Uniform: true}
+	}
+
+	first := img.RGBAAt(b.Min.X, b.Min.Y)
+	ref := [4]uint8{first.R, first.G, first.B, first.A}
+	uniform := true
+	black := 0
+
+	// 按块记录是否全部为近黑像素，用于查找受保护区域
+	cols := (b.Dx() + protectedCellSize - 1) / protectedCellSize
+	rows := (b.Dy() + protectedCellSize - 1) / protectedCellSize
+	cells := make([]bool, cols*rows)
+	for i := range cells {
+		cells[i] = true
+	}
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		row := img.Pix[img.PixOffset(b.Min.X, y):img.PixOffset(b.Max.X, y)]
+		cy := (y - b.Min.Y) / protectedCellSize
+		for i := 0; i < len(row); i += 4 {
+			for c := 0; uniform && c < 4; c++ {
+				diff := int(row[i+c]) - int(ref[c])
+				uniform = diff <= uniformTolerance && -diff <= uniformTolerance
+			}
+			if row[i+0] <= NearBlackThreshold && row[i+1] <= NearBlackThreshold && row[i+2] <= NearBlackThreshold {
+				black++
+			} else {
+				cells[cy*cols+i/4/protectedCellSize] = false
+			}
+		}
+	}
+
+	q := &FrameQuality{
+		Uniform:    uniform,
+		Color:      first,
+		BlackRatio: float64(black) / float64(b.Dx()*b.Dy()),
+	}
+	if !uniform {
+		q.Color = AverageColor(img, b)
+	}
+	if !q.IsBlank() {
+		q.ProtectedRects = blackRects(cells, cols, rows, b)
+	}
+	return q
+}
+
+// blackRects 在分块结果中查找由近黑块组成的实心矩形，返回其中足够大的区域
+func blackRects(cells []bool, cols, rows int, bounds image.Rectangle) []image.Rectangle {
+	var rects []image.Rectangle
+	seen := make([]bool, len(cells))
+	var stack []int
+
+	for start := range cells {
+		if !cells[start] || seen[start] {
+			continue
+		}
+
+		// 四连通填充，统计块数和外接矩形
+		minX, minY := start%cols, start/cols
+		maxX, maxY := minX, minY
+		count := 0
+		seen[start] = true
+		stack = append(stack[:0], start)
+		for len(stack) > 0 {
+			i := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			count++
+
+			x, y := i%cols, i/cols
+			minX, maxX = min(minX, x), max(maxX, x)
+			minY, maxY = min(minY, y), max(maxY, y)
+
+			for _, n := range [4][2]int{{x - 1, y}, {x + 1, y}, {x, y - 1}, {x, y + 1}} {
+				if n[0] < 0 || n[0] >= cols || n[1] < 0 || n[1] >= rows {
+					continue
+				}
+				j := n[1]*cols + n[0]
+				if cells[j] && !seen[j] {
+					seen[j] = true
+					stack = append(stack, j)
+				}
+			}
+		}
+
+		// 只保留实心矩形，不规则的黑色区域更可能是正常内容
+		if count != (maxX-minX+1)*(maxY-minY+1) {
+			continue
+		}
+		r := image.Rect(
+			minX*protectedCellSize, minY*protectedCellSize,
+			(maxX+1)*protectedCellSize, (maxY+1)*protectedCellSize,
+		).Add(bounds.Min).Intersect(bounds)
+		if r.Dx() >= protectedMinSize && r.Dy() >= protectedMinSize {
+			rects = append(rects, r)
+		}
+	}
+
+	return rects
+}
+
+// BlankFrameError 在 CaptureOptions.RejectBlankFrames 拒绝空白帧时返回
+// errors.Is(err, ErrBlankFrame) 为 true
+type BlankFrameError struct {
+	// Quality 被拒绝的帧的质量
+	Quality *FrameQuality
+}
+
+func (e *BlankFrameError) Error() string {
+	return "blank frame: " + e.Quality.String()
+}
+
+// Is 使 errors.Is(err, ErrBlankFrame) 成立
+func (e *BlankFrameError) Is(target error) bool {
+	return target == ErrBlankFrame
+}
@@ -0,0 +1,82 @@
+package xcap
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestAnalyzeFrameBlank(t *testing.T) {
+	white := color.RGBA{255, 255, 255, 255}
+
+	black := solidImage(100, 100, color.RGBA{A: 255})
+	if q := AnalyzeFrame(black); !q.Uniform || !q.IsBlank() || q.BlackRatio != 1 || len(q.ProtectedRects) != 0 {
+		t.Errorf("black frame: got %+v", q)
+	}
+
+	// 轻微噪声仍视为纯色
+	wall := solidImage(100, 100, white)
+	wall.SetRGBA(50, 50, color.RGBA{254, 253, 255, 255})
+	if q := AnalyzeFrame(wall); !q.Uniform || q.Color != white || !q.IsBlank() {
+		t.Errorf("uniform frame: got %+v", q)
+	}
+
+	// 只有少量亮像素的黑色画面（如休眠屏幕上的光标）
+	dark := solidImage(100, 100, color.RGBA{A: 255})
+	fillRect(dark, image.Rect(0, 0, 5, 5), white)
+	if q := AnalyzeFrame(dark); q.Uniform || !q.IsBlank() {
+		t.Errorf("near-black frame: got %+v", q)
+	}
+
+	if q := AnalyzeFrame(image.NewRGBA(image.Rectangle{})); !q.IsBlank() {
+		t.Error("empty frame should be blank")
+	}
+}
+
+func TestAnalyzeFrameProtectedRects(t *testing.T) {
+	img := solidImage(400, 300, color.RGBA{200, 200, 200, 255})
+	video := image.Rect(80, 64, 320, 200)
+	fillRect(img, video, color.RGBA{A: 255})
+
+	// 小块黑色内容和不规则黑色区域不应被当作受保护区域
+	fillRect(img, image.Rect(8, 8, 40, 40), color.RGBA{A: 255})
+	fillRect(img, image.Rect(0, 224, 200, 296), color.RGBA{A: 255})
+	fillRect(img, image.Rect(0, 224, 96, 256), color.RGBA{255, 255, 255, 255})
+
+	q := AnalyzeFrame(img)
+	if q.IsBlank() {
+		t.Fatalf("frame should not be blank: %+v", q)
+	}
+	if len(q.ProtectedRects) != 1 || q.ProtectedRects[0] != video {
+		t.Errorf("ProtectedRects = %v, want [%v]", q.ProtectedRects, video)
+	}
+}
+
+func TestCaptureWithOptionsRejectBlankFrames(t *testing.T) {
+	m := &fakeMonitor{id: 3, width: 10, height: 10, img: solidImage(10, 10, color.RGBA{A: 255})}
+
+	if img, err := CaptureMonitorWithOptions(m, CaptureOptions{}); err != nil || img == nil {
+		t.Fatalf("default options should return the frame, got %v", err)
+	}
+
+	_, err := CaptureMonitorWithOptions(m, CaptureOptions{RejectBlankFrames: true})
+	if !errors.Is(err, ErrBlankFrame) {
+		t.Fatalf("expected ErrBlankFrame, got %v", err)
+	}
+	var be *BlankFrameError
+	if !errors.As(err, &be) || !be.Quality.Uniform {
+		t.Errorf("expected *BlankFrameError with quality, got %v", err)
+	}
+	var xe *Error
+	if !errors.As(err, &xe) || xe.ID != 3 {
+		t.Errorf("expected *Error for monitor 3, got %v", err)
+	}
+
+	img := solidImage(10, 10, color.RGBA{A: 255})
+	fillRect(img, image.Rect(4, 4, 6, 6), color.RGBA{255, 0, 0, 255})
+	w := &fakeWindow{id: 4, width: 10, height: 10, img: img}
+	if _, err := CaptureWindowWithOptions(w, CaptureOptions{RejectBlankFrames: true}); err != nil {
+		t.Errorf("window with content rejected: %v", err)
+	}
+}
@@ -0,0 +1,311 @@
+package xcap
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// 截图脱敏：在保存截图前遮盖敏感窗口和固定区域
+
+// RedactMode 指定遮盖方式
+type RedactMode int
+
+const (
+	// RedactBlackout 用不透明黑色填充，唯一不保留任何原始信息的方式
+	RedactBlackout RedactMode = iota
+
+	// RedactPixelate 马赛克，以块内平均颜色填充
+	RedactPixelate
+
+	// RedactBlur 高斯模糊。较小的模糊半径下大号文字仍可能被辨认，
+	// 遮盖密码等机密信息时请使用 RedactBlackout
+	RedactBlur
+)
+
+// 脱敏默认参数
+const (
+	// DefaultPixelateSize 默认的马赛克块边长（像素）
+	DefaultPixelateSize = 16
+
+	// DefaultBlurSigma 默认的高斯模糊标准差（像素）
+	DefaultBlurSigma = 12
+)
+
+// RedactRule 描述需要遮盖的内容
+//
+// Rect 非空时规则表示一个固定的全局坐标区域，忽略其余匹配条件；
+// 否则规则匹配窗口，所有非零条件都满足时才算匹配。
+// 所有条件均为零值的规则不匹配任何内容。
+type RedactRule struct {
+	// AppName 应用程序名称，完全匹配（不区分大小写）
+	AppName string
+
+	// Title 窗口标题正则表达式
+	Title *regexp.Regexp
+
+	// PID 进程 ID
+	PID uint32
+
+	// Rect 固定区域，与 Monitor.X()/Y() 相同的全局坐标系
+	Rect image.Rectangle
+
+	// Mode 遮盖方式
+	Mode RedactMode
+}
+
+// MatchWindow 判断规则是否匹配窗口 w，固定区域规则总是返回 false
+func (r RedactRule) MatchWindow(w Window) bool {
+	if !r.Rect.Empty() || (r.AppName == "" && r.Title == nil && r.PID == 0) {
+		return false
+	}
+	if r.AppName != "" && !strings.EqualFold(w.AppName(), r.AppName) {
+		return false
+	}
+	if r.Title != nil && !r.Title.MatchString(w.Title()) {
+		return false
+	}
+	if r.PID != 0 && w.PID() != r.PID {
+		return false
+	}
+	return true
+}
+
+// Redaction 记录一次遮盖
+type Redaction struct {
+	// Rule 命中的规则在 Redactor.Rules 中的下标
+	Rule int
+
+	// Window 被遮盖的窗口，固定区域规则为 nil
+	Window Window
+
+	// Regions 实际遮盖的区域（全局坐标）。
+	// 窗口规则默认遮盖整个窗口矩形；启用 Redactor.OcclusionAware 时只遮盖
+	// 未被其他窗口挡住的部分，被完全挡住的窗口不会出现在报告中
+	Regions []image.Rectangle
+
+	// Mode 遮盖方式
+	Mode RedactMode
+}
+
+// Bounds 返回所有遮盖区域的外接矩形（全局坐标）
+func (r Redaction) Bounds() image.Rectangle {
+	return boundingRect(r.Regions)
+}
+
+// Redactor 在截图上按规则遮盖敏感窗口和区域
+//
+// 窗口位置来自 AllWindows() 的几何信息，默认遮盖匹配窗口的整个矩形，
+// 即使它被其他窗口挡住：前面的窗口可能是透明或半透明的，
+// 按几何遮挡关系裁剪会让敏感内容透过它们泄漏出去。
+// 窗口列表在截图之前获取，两者之间新打开或移动的窗口无法被发现，
+// 因此不应把脱敏作为唯一的防泄漏手段。
+type Redactor struct {
+	// Rules 脱敏规则，每个窗口使用第一条匹配的规则
+	Rules []RedactRule
+
+	// PixelateSize 马赛克块边长（像素），为 0 时使用 DefaultPixelateSize
+	PixelateSize int
+
+	// BlurSigma 高斯模糊标准差（像素），为 0 时使用 DefaultBlurSigma
+	BlurSigma float64
+
+	// OcclusionAware 为 true 时按 Z 顺序只遮盖窗口未被其他窗口挡住的部分，
+	// 保留前面窗口的内容。遮挡只按矩形判断，不考虑透明度，
+	// 前面是透明窗口、阴影或异形窗口时敏感内容会露出，只应在确定没有这类窗口时启用
+	OcclusionAware bool
+}
+
+// CaptureDesktop 截取整个桌面（见 CaptureDesktop）并应用脱敏规则
+func (r *Redactor) CaptureDesktop() (*image.RGBA, []Redaction, error) {
+	monitors, err := AllMonitors()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	windows, err := r.windows()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	img, area, err := captureDesktop(monitors)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return img, r.Apply(img, area, windows), nil
+}
+
+// CaptureMonitor 截取显示器并应用脱敏规则
+func (r *Redactor) CaptureMonitor(m Monitor) (*image.RGBA, []Redaction, error) {
+	windows, err := r.windows()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	img, err := m.CaptureImage()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return img, r.Apply(img, monitorRect(m), windows), nil
+}
+
+// windows 在存在窗口规则时返回当前所有窗口
+// 获取失败时返回错误而不是跳过脱敏
+func (r *Redactor) windows() ([]Window, error) {
+	for _, rule := range r.Rules {
+		if rule.Rect.Empty() {
+			return AllWindows()
+		}
+	}
+	return nil, nil
+}
+
+// Apply 在 img 上应用脱敏规则并返回遮盖记录
+//
+// img 覆盖全局坐标区域 area（截图像素与 area 的比例即缩放因子），
+// windows 为当前的窗口列表，可以是任意顺序。
+func (r *Redactor) Apply(img *image.RGBA, area image.Rectangle, windows []Window) []Redaction {
+	var redactions []Redaction
+
+	sorted := sortFrontToBack(windows)
+	rects := make([]image.Rectangle, len(sorted))
+	targets := make([][]image.Rectangle, len(sorted))
+	for i, w := range sorted {
+		rects[i] = windowRect(w)
+		targets[i] = rects[i : i+1]
+	}
+	if r.OcclusionAware {
+		targets = visibleRegions(rects)
+	}
+
+	for i, w := range sorted {
+		for ri, rule := range r.Rules {
+			if !rule.MatchWindow(w) {
+				continue
+			}
+			if regions := r.redact(img, area, targets[i], rule.Mode); len(regions) > 0 {
+				redactions = append(redactions, Redaction{Rule: ri, Window: w, Regions: regions, Mode: rule.Mode})
+			}
+			break
+		}
+	}
+
+	for ri, rule := range r.Rules {
+		if rule.Rect.Empty() {
+			continue
+		}
+		if regions := r.redact(img, area, []image.Rectangle{rule.Rect}, rule.Mode); len(regions) > 0 {
+			redactions = append(redactions, Redaction{Rule: ri, Regions: regions, Mode: rule.Mode})
+		}
+	}
+
+	return redactions
+}
+
+// redact 遮盖 regions 中与 area 相交的部分，返回实际遮盖的区域（全局坐标）
+func (r *Redactor) redact(img *image.RGBA, area image.Rectangle, regions []image.Rectangle, mode RedactMode) []image.Rectangle {
+	var done []image.Rectangle
+	for _, g := range regions {
+		g = g.Intersect(area)
+		p := toPixelRect(g, area, img)
+		if p.Empty() {
+			continue
+		}
+
+		switch mode {
+		case RedactPixelate:
+			size := r.PixelateSize
+			if size <= 0 {
+				size = DefaultPixelateSize
+			}
+			pixelate(img, p, size)
+		case RedactBlur:
+			sigma := r.BlurSigma
+			if sigma <= 0 {
+				sigma = DefaultBlurSigma
+			}
+			gaussianBlur(img, p, sigma)
+		default:
+			fillRect(img, p, color.RGBA{A: 255})
+		}
+
+		done = append(done, g)
+	}
+	return done
+}
+
+// pixelate 将区域 r 划分为 size x size 的块，每块以平均颜色填充
+func pixelate(img *image.RGBA, r image.Rectangle, size int) {
+	for y := r.Min.Y; y < r.Max.Y; y += size {
+		for x := r.Min.X; x < r.Max.X; x += size {
+			block := image.Rect(x, y, x+size, y+size).Intersect(r)
+			fillRect(img, block, AverageColor(img, block))
+		}
+	}
+}
+
+// gaussianBlur 对区域 r 做可分离的高斯模糊
+// 只采样区域内的像素（边缘像素向外延伸），区域外的内容不会混入，区域内的内容也不会外溢
+func gaussianBlur(img *image.RGBA, r image.Rectangle, sigma float64) {
+	radius := int(math.Ceil(sigma * 3))
+	kernel := make([]float64, 2*radius+1)
+	var total float64
+	for i := range kernel {
+		d := float64(i - radius)
+		kernel[i] = math.Exp(-d * d / (2 * sigma * sigma))
+		total += kernel[i]
+	}
+	for i := range kernel {
+		kernel[i] /= total
+	}
+
+	w, h := r.Dx(), r.Dy()
+	clamp := func(v, n int) int {
+		if v < 0 {
+			return 0
+		}
+		if v >= n {
+			return n - 1
+		}
+		return v
+	}
+
+	// 水平方向，结果写入临时缓冲区
+	tmp := make([]float64, w*h*4)
+	for y := 0; y < h; y++ {
+		row := img.Pix[img.PixOffset(r.Min.X, r.Min.Y+y):]
+		for x := 0; x < w; x++ {
+			var acc [4]float64
+			for k, kv := range kernel {
+				p := row[clamp(x+k-radius, w)*4:]
+				acc[0] += float64(p[0]) * kv
+				acc[1] += float64(p[1]) * kv
+				acc[2] += float64(p[2]) * kv
+				acc[3] += float64(p[3]) * kv
+			}
+			copy(tmp[(y*w+x)*4:], acc[:])
+		}
+	}
+
+	// 垂直方向，结果写回图像
+	for y := 0; y < h; y++ {
+		row := img.Pix[img.PixOffset(r.Min.X, r.Min.Y+y):]
+		for x := 0; x < w; x++ {
+			var acc [4]float64
+			for k, kv := range kernel {
+				p := tmp[(clamp(y+k-radius, h)*w+x)*4:]
+				acc[0] += p[0] * kv
+				acc[1] += p[1] * kv
+				acc[2] += p[2] * kv
+				acc[3] += p[3] * kv
+			}
+			for c := 0; c < 4; c++ {
+				row[x*4+c] = uint8(math.Min(255, math.Round(acc[c])))
+			}
+		}
+	}
+}
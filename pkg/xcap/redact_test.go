@@ -0,0 +1,140 @@
+package xcap
+
+import (
+	"image"
+	"image/color"
+	"regexp"
+	"testing"
+)
+
+func TestSubtractRect(t *testing.T) {
+	r := image.Rect(0, 0, 10, 10)
+
+	parts := subtractRect(r, image.Rect(3, 3, 6, 6))
+	if len(parts) != 4 {
+		t.Fatalf("subtractRect returned %d parts, want 4: %v", len(parts), parts)
+	}
+	area := 0
+	for i, p := range parts {
+		area += p.Dx() * p.Dy()
+		for _, q := range parts[i+1:] {
+			if p.Overlaps(q) {
+				t.Errorf("parts %v and %v overlap", p, q)
+			}
+		}
+	}
+	if area != 100-9 {
+		t.Errorf("remaining area = %d, want 91", area)
+	}
+
+	if parts := subtractRect(r, image.Rect(-5, -5, 20, 20)); len(parts) != 0 {
+		t.Errorf("fully covered: got %v, want none", parts)
+	}
+	if parts := subtractRect(r, image.Rect(20, 20, 30, 30)); len(parts) != 1 || parts[0] != r {
+		t.Errorf("disjoint: got %v, want [%v]", parts, r)
+	}
+}
+
+func TestRedactorApply(t *testing.T) {
+	gray := color.RGBA{128, 128, 128, 255}
+	black := color.RGBA{A: 255}
+
+	// 显示器位于全局坐标 (100, 0)，截图与几何尺寸 1:1
+	area := image.Rect(100, 0, 300, 100)
+
+	for _, c := range []struct {
+		name           string
+		occlusionAware bool
+		vault          image.Rectangle
+		hidden         color.RGBA
+	}{
+		// 默认遮盖整个窗口：前面的编辑器可能是透明的
+		{"full window", false, image.Rect(120, 10, 200, 50), black},
+		// 按遮挡关系只遮盖可见部分
+		{"occlusion aware", true, image.Rect(120, 10, 160, 50), gray},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			img := image.NewRGBA(image.Rect(0, 0, 200, 100))
+			fillRect(img, img.Bounds(), gray)
+
+			// 前面的编辑器窗口挡住了密码管理器的右半部分
+			editor := &fakeWindow{id: 1, appName: "Editor", x: 160, y: 0, width: 100, height: 100}
+			vault := &fakeWindow{id: 2, appName: "1Password", title: "Vault", x: 120, y: 10, width: 80, height: 40}
+			chat := &fakeWindow{id: 3, appName: "Chat", title: "secret channel", x: 0, y: 0, width: 50, height: 50}
+
+			r := &Redactor{Rules: []RedactRule{
+				{AppName: "1password"},
+				{Title: regexp.MustCompile(`secret`)},
+				{Rect: image.Rect(280, 80, 320, 120)},
+			}, OcclusionAware: c.occlusionAware}
+			redactions := r.Apply(img, area, []Window{editor, vault, chat})
+
+			if len(redactions) != 2 {
+				t.Fatalf("got %d redactions, want 2 (chat window is off-monitor): %+v", len(redactions), redactions)
+			}
+			if got := redactions[0]; got.Window != vault || got.Rule != 0 || got.Bounds() != c.vault {
+				t.Errorf("vault redaction = rule %d window %v bounds %v, want bounds %v", got.Rule, got.Window, got.Bounds(), c.vault)
+			}
+			if got := redactions[1]; got.Window != nil || got.Rule != 2 || got.Bounds() != image.Rect(280, 80, 300, 100) {
+				t.Errorf("rect redaction = rule %d window %v bounds %v", got.Rule, got.Window, got.Bounds())
+			}
+
+			for _, p := range []struct {
+				x, y int
+				want color.RGBA
+			}{
+				{20, 10, black},    // 密码管理器可见部分
+				{59, 49, black},    // 可见部分右下角
+				{60, 10, c.hidden}, // 被编辑器挡住的部分
+				{99, 49, c.hidden},
+				{100, 10, gray},  // 窗口右侧
+				{19, 10, gray},   // 窗口左侧
+				{190, 90, black}, // 固定区域
+				{179, 90, gray},
+			} {
+				if got := img.RGBAAt(p.x, p.y); got != p.want {
+					t.Errorf("pixel (%d, %d) = %v, want %v", p.x, p.y, got, p.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRedactorScaledModes(t *testing.T) {
+	// 2x 缩放：几何 50x50，截图 100x100 像素
+	img := noiseImage(100, 100, 7)
+	orig := cropRGBA(img, img.Bounds())
+	area := image.Rect(0, 0, 50, 50)
+	w := &fakeWindow{pid: 42, x: 10, y: 10, width: 20, height: 20}
+
+	for _, mode := range []RedactMode{RedactPixelate, RedactBlur} {
+		copy(img.Pix, orig.Pix)
+
+		r := &Redactor{Rules: []RedactRule{{PID: 42, Mode: mode}}, PixelateSize: 8, BlurSigma: 4}
+		if got := r.Apply(img, area, []Window{w}); len(got) != 1 {
+			t.Fatalf("mode %d: got %d redactions, want 1", mode, len(got))
+		}
+
+		// 遮盖只影响像素区域 (20, 20)-(60, 60)
+		changed := 0
+		for y := 0; y < 100; y++ {
+			for x := 0; x < 100; x++ {
+				inside := image.Pt(x, y).In(image.Rect(20, 20, 60, 60))
+				same := img.RGBAAt(x, y) == orig.RGBAAt(x, y)
+				if !inside && !same {
+					t.Fatalf("mode %d: pixel (%d, %d) outside the window was modified", mode, x, y)
+				}
+				if inside && !same {
+					changed++
+				}
+			}
+		}
+		if changed < 40*40/2 {
+			t.Errorf("mode %d: only %d pixels changed inside the window", mode, changed)
+		}
+	}
+
+	if got := (&Redactor{Rules: []RedactRule{{}}}).Apply(img, area, []Window{w}); len(got) != 0 {
+		t.Errorf("empty rule matched: %+v", got)
+	}
+}
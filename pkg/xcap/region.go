@@ -0,0 +1,116 @@
+package xcap
+
+import (
+	"image"
+	"math"
+	"sort"
+)
+
+// 矩形区域运算，用于根据窗口几何和 Z 顺序计算可见区域
+
+// monitorRect 返回显示器在全局坐标系中的矩形
+func monitorRect(m Monitor) image.Rectangle {
+	return image.Rect(m.X(), m.Y(), m.X()+int(m.Width()), m.Y()+int(m.Height()))
+}
+
+// windowRect 返回窗口在全局坐标系中的矩形
+func windowRect(w Window) image.Rectangle {
+	return image.Rect(w.X(), w.Y(), w.X()+int(w.Width()), w.Y()+int(w.Height()))
+}
+
+// sortFrontToBack 返回按 Z 顺序从前到后排列的窗口副本
+// Z 相同的窗口保持原有顺序（两个平台的枚举顺序本身即为从前到后）
+func sortFrontToBack(windows []Window) []Window {
+	sorted := make([]Window, len(windows))
+	copy(sorted, windows)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Z() > sorted[j].Z()
+	})
+	return sorted
+}
+
+// subtractRect 返回 r 减去 hole 后剩余的部分，结果为最多 4 个互不相交的矩形
+func subtractRect(r, hole image.Rectangle) []image.Rectangle {
+	in := r.Intersect(hole)
+	if in.Empty() {
+		return []image.Rectangle{r}
+	}
+
+	var rs []image.Rectangle
+	// 上下两条占满整个宽度，左右两条只占中间的高度
+	if in.Min.Y > r.Min.Y {
+		rs = append(rs, image.Rect(r.Min.X, r.Min.Y, r.Max.X, in.Min.Y))
+	}
+	if in.Max.Y < r.Max.Y {
+		rs = append(rs, image.Rect(r.Min.X, in.Max.Y, r.Max.X, r.Max.Y))
+	}
+	if in.Min.X > r.Min.X {
+		rs = append(rs, image.Rect(r.Min.X, in.Min.Y, in.Min.X, in.Max.Y))
+	}
+	if in.Max.X < r.Max.X {
+		rs = append(rs, image.Rect(in.Max.X, in.Min.Y, r.Max.X, in.Max.Y))
+	}
+	return rs
+}
+
+// subtractRects 从一组互不相交的矩形中减去 hole
+func subtractRects(rs []image.Rectangle, hole image.Rectangle) []image.Rectangle {
+	var out []image.Rectangle
+	for _, r := range rs {
+		out = append(out, subtractRect(r, hole)...)
+	}
+	return out
+}
+
+// visibleRegions 计算一组按从前到后排列的矩形各自未被前面矩形遮挡的部分
+func visibleRegions(rects []image.Rectangle) [][]image.Rectangle {
+	visible := make([][]image.Rectangle, len(rects))
+	for i, r := range rects {
+		if r.Empty() {
+			continue
+		}
+		parts := []image.Rectangle{r}
+		for _, front := range rects[:i] {
+			parts = subtractRects(parts, front)
+			if len(parts) == 0 {
+				break
+			}
+		}
+		visible[i] = parts
+	}
+	return visible
+}
+
+// boundingRect 返回一组矩形的外接矩形
+func boundingRect(rs []image.Rectangle) image.Rectangle {
+	var b image.Rectangle
+	for _, r := range rs {
+		b = b.Union(r)
+	}
+	return b
+}
+
+// toPixelRect 将全局坐标矩形 r 换算为 img 中的像素矩形
+// img 覆盖全局坐标区域 area；换算时向外取整，结果裁剪到图像范围内
+func toPixelRect(r, area image.Rectangle, img *image.RGBA) image.Rectangle {
+	return scaleRect(r, area, img.Bounds()).Intersect(img.Bounds())
+}
+
+// scaleRect 将全局坐标矩形 r 换算为像素区域 b 中的矩形，b 覆盖全局坐标区域 area
+// 换算时向外取整，结果不做裁剪
+func scaleRect(r, area, b image.Rectangle) image.Rectangle {
+	if area.Empty() {
+		return image.Rectangle{}
+	}
+
+	sx := float64(b.Dx()) / float64(area.Dx())
+	sy := float64(b.Dy()) / float64(area.Dy())
+	r = r.Sub(area.Min)
+
+	return image.Rect(
+		b.Min.X+int(math.Floor(float64(r.Min.X)*sx)),
+		b.Min.Y+int(math.Floor(float64(r.Min.Y)*sy)),
+		b.Min.X+int(math.Ceil(float64(r.Max.X)*sx)),
+		b.Min.Y+int(math.Ceil(float64(r.Max.Y)*sy)),
+	)
+}
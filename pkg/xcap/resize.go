@@ -0,0 +1,220 @@
+package xcap
+
+import (
+	"fmt"
+	"image"
+	"math"
+)
+
+// ResampleFilter 指定缩放图像时使用的重采样滤波器
+type ResampleFilter int
+
+const (
+	// ResampleLanczos Lanczos-3 滤波，缩小后文字和细线最清晰，速度最慢
+	ResampleLanczos ResampleFilter = iota
+
+	// ResampleBilinear 双线性（三角）滤波，缩小时按比例扩大采样范围，不会产生锯齿
+	ResampleBilinear
+
+	// ResampleBox 区域平均，速度最快，适合缩略图；
+	// 整数倍缩小（如 2x 屏幕截图缩为逻辑分辨率）时直接按块求平均
+	ResampleBox
+)
+
+// String 返回滤波器的名称
+func (f ResampleFilter) String() string {
+	switch f {
+	case ResampleBilinear:
+		return "bilinear"
+	case ResampleBox:
+		return "box"
+	case ResampleLanczos:
+		return "lanczos"
+	}
+	return fmt.Sprintf("ResampleFilter(%d)", int(f))
+}
+
+// Resize 将 img 缩放为 width x height，返回的图像 Bounds() 从 (0, 0) 开始
+// 颜色在预乘 alpha 的 RGBA 空间中插值，透明边缘不会出现色晕。
+// 尺寸与原图相同时返回副本；width 或 height <= 0 时返回 ErrInvalidRegion，
+// filter 不是已定义的滤波器时返回 ErrInvalidFilter
+func Resize(img *image.RGBA, width, height int, filter ResampleFilter) (*image.RGBA, error) {
+	if width <= 0 || height <= 0 {
+		return nil, ErrInvalidRegion
+	}
+	k, ok := resampleKernels[filter]
+	if !ok {
+		return nil, ErrInvalidFilter
+	}
+
+	b := img.Bounds()
+	if b.Dx() == width && b.Dy() == height {
+		dst := cropRGBA(img, b)
+		dst.Rect = dst.Rect.Sub(b.Min)
+		return dst, nil
+	}
+	if b.Empty() {
+		return image.NewRGBA(image.Rect(0, 0, width, height)), nil
+	}
+
+	if filter == ResampleBox && b.Dx()%width == 0 && b.Dy()%height == 0 {
+		return boxDownscale(img, b.Dx()/width, b.Dy()/height), nil
+	}
+
+	xw := resampleWeights(b.Dx(), width, k)
+	yw := resampleWeights(b.Dy(), height, k)
+
+	// 水平方向：src 的每一行缩放为 width 列，结果写入浮点缓冲区
+	tmp := make([]float32, b.Dy()*width*4)
+	for y := 0; y < b.Dy(); y++ {
+		row := img.Pix[img.PixOffset(b.Min.X, b.Min.Y+y):]
+		out := tmp[y*width*4:]
+		for x, w := range xw {
+			var acc [4]float32
+			for i, wv := range w.weights {
+				p := row[(w.start+i)*4:]
+				acc[0] += float32(p[0]) * wv
+				acc[1] += float32(p[1]) * wv
+				acc[2] += float32(p[2]) * wv
+				acc[3] += float32(p[3]) * wv
+			}
+			copy(out[x*4:], acc[:])
+		}
+	}
+
+	// 垂直方向：写回 8 位像素，并保证预乘后颜色不超过 alpha
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y, w := range yw {
+		row := dst.Pix[dst.PixOffset(0, y):]
+		for x := 0; x < width; x++ {
+			var acc [4]float32
+			for i, wv := range w.weights {
+				p := tmp[((w.start+i)*width+x)*4:]
+				acc[0] += p[0] * wv
+				acc[1] += p[1] * wv
+				acc[2] += p[2] * wv
+				acc[3] += p[3] * wv
+			}
+			a := clampUint8(acc[3])
+			for c := 0; c < 3; c++ {
+				row[x*4+c] = min(clampUint8(acc[c]), a)
+			}
+			row[x*4+3] = a
+		}
+	}
+
+	return dst, nil
+}
+
+// boxDownscale 将 img 按整数倍 fx、fy 缩小，每个输出像素为对应块的平均值
+func boxDownscale(img *image.RGBA, fx, fy int) *image.RGBA {
+	b := img.Bounds()
+	width, height := b.Dx()/fx, b.Dy()/fy
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	n := uint32(fx * fy)
+
+	sums := make([]uint32, width*4)
+	for y := 0; y < height; y++ {
+		clear(sums)
+		for sy := 0; sy < fy; sy++ {
+			row := img.Pix[img.PixOffset(b.Min.X, b.Min.Y+y*fy+sy):]
+			for x := 0; x < width; x++ {
+				for sx := 0; sx < fx; sx++ {
+					p := row[(x*fx+sx)*4:]
+					sums[x*4+0] += uint32(p[0])
+					sums[x*4+1] += uint32(p[1])
+					sums[x*4+2] += uint32(p[2])
+					sums[x*4+3] += uint32(p[3])
+				}
+			}
+		}
+
+		out := dst.Pix[dst.PixOffset(0, y):]
+		for i, s := range sums {
+			out[i] = uint8((s + n/2) / n)
+		}
+	}
+
+	return dst
+}
+
+// resampleKernel 描述一个以 0 为中心的对称重采样核
+type resampleKernel struct {
+	support float64
+	at      func(x float64) float64
+}
+
+var resampleKernels = map[ResampleFilter]resampleKernel{
+	ResampleBox: {0.5, func(x float64) float64 {
+		if x >= -0.5 && x < 0.5 {
+			return 1
+		}
+		return 0
+	}},
+	ResampleBilinear: {1, func(x float64) float64 {
+		return math.Max(0, 1-math.Abs(x))
+	}},
+	ResampleLanczos: {3, func(x float64) float64 {
+		if x == 0 {
+			return 1
+		}
+		if x <= -3 || x >= 3 {
+			return 0
+		}
+		px := math.Pi * x
+		return 3 * math.Sin(px) * math.Sin(px/3) / (px * px)
+	}},
+}
+
+// resampleWeight 是一个输出像素对应的连续输入像素 [start, start+len(weights)) 及其权重
+type resampleWeight struct {
+	start   int
+	weights []float32
+}
+
+// resampleWeights 计算把长度 src 缩放为 dst 时每个输出像素的权重
+// 缩小时核按缩放比例展开，使每个输入像素都参与计算
+func resampleWeights(src, dst int, k resampleKernel) []resampleWeight {
+	scale := float64(src) / float64(dst)
+	spread := math.Max(scale, 1)
+	support := k.support * spread
+
+	ws := make([]resampleWeight, dst)
+	for i := range ws {
+		center := (float64(i)+0.5)*scale - 0.5
+		start := max(int(math.Ceil(center-support)), 0)
+		end := min(int(math.Floor(center+support)), src-1)
+
+		weights := make([]float32, 0, end-start+1)
+		var total float64
+		for j := start; j <= end; j++ {
+			w := k.at((float64(j) - center) / spread)
+			weights = append(weights, float32(w))
+			total += w
+		}
+
+		// 边缘被截断的核重新归一化；全为 0 时（如放大时的盒式核）取最近的像素
+		if total == 0 {
+			nearest := min(max(int(math.Round(center)), 0), src-1)
+			ws[i] = resampleWeight{start: nearest, weights: []float32{1}}
+			continue
+		}
+		for j := range weights {
+			weights[j] /= float32(total)
+		}
+		ws[i] = resampleWeight{start: start, weights: weights}
+	}
+
+	return ws
+}
+
+// clampUint8 将 v 四舍五入并限制在 0-255
+func clampUint8(v float32) uint8 {
+	if v <= 0 {
+		return 0
+	}
+	if v >= 255 {
+		return 255
+	}
+	return uint8(v + 0.5)
+}
@@ -0,0 +1,91 @@
+package xcap
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestResizeBoxIntegerDownscale(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	fillRect(img, image.Rect(0, 0, 2, 2), color.RGBA{200, 100, 0, 255})
+	img.SetRGBA(2, 0, color.RGBA{100, 0, 0, 255})
+	img.SetRGBA(3, 1, color.RGBA{100, 0, 0, 255})
+
+	dst, err := Resize(img, 2, 1, ResampleBox)
+	if err != nil {
+		t.Fatalf("Resize failed: %v", err)
+	}
+	if got := dst.RGBAAt(0, 0); got != (color.RGBA{200, 100, 0, 255}) {
+		t.Errorf("pixel 0 = %v", got)
+	}
+	// 两个不透明像素和两个透明像素的平均值
+	if got := dst.RGBAAt(1, 0); got != (color.RGBA{50, 0, 0, 128}) {
+		t.Errorf("pixel 1 = %v", got)
+	}
+}
+
+func TestResizeFilters(t *testing.T) {
+	img := checkerImage(64, 48, 1)
+	grey := color.RGBA{128, 128, 128, 255}
+
+	for _, f := range []ResampleFilter{ResampleBox, ResampleBilinear, ResampleLanczos} {
+		// 非整数倍缩小：棋盘格应平均为灰色，而不是保留黑白（锯齿）
+		dst, err := Resize(img, 24, 18, f)
+		if err != nil {
+			t.Fatalf("%v: Resize failed: %v", f, err)
+		}
+		if dst.Bounds() != image.Rect(0, 0, 24, 18) {
+			t.Fatalf("%v: bounds = %v", f, dst.Bounds())
+		}
+		got := dst.RGBAAt(12, 9)
+		for c, v := range []uint8{got.R, got.G, got.B} {
+			if diff := int(v) - int(grey.R); diff > 24 || diff < -24 {
+				t.Errorf("%v: channel %d = %d, want about %d", f, c, v, grey.R)
+			}
+		}
+
+		// 放大纯色图像保持颜色不变
+		solid := solidImage(3, 3, color.RGBA{10, 20, 30, 255})
+		up, err := Resize(solid, 7, 5, f)
+		if err != nil {
+			t.Fatalf("%v: Resize failed: %v", f, err)
+		}
+		if got := up.RGBAAt(6, 4); got != (color.RGBA{10, 20, 30, 255}) {
+			t.Errorf("%v: upscaled pixel = %v", f, got)
+		}
+	}
+
+	if _, err := Resize(img, 0, 10, ResampleBox); err != ErrInvalidRegion {
+		t.Errorf("expected ErrInvalidRegion, got %v", err)
+	}
+	// 未定义的滤波器（如 CaptureOptions.Filter 填错）返回错误而不是 panic
+	for _, size := range []image.Point{{24, 18}, {64, 48}} {
+		if _, err := Resize(img, size.X, size.Y, ResampleFilter(42)); err != ErrInvalidFilter {
+			t.Errorf("Resize to %v with unknown filter: got %v, want ErrInvalidFilter", size, err)
+		}
+	}
+}
+
+func TestCaptureOptionsResize(t *testing.T) {
+	m := &fakeMonitor{width: 100, height: 50, scaleFactor: 2, img: solidImage(200, 100, color.RGBA{B: 255, A: 255})}
+
+	for _, c := range []struct {
+		opts CaptureOptions
+		want image.Rectangle
+	}{
+		{CaptureOptions{Scale: 0.5}, image.Rect(0, 0, 100, 50)},
+		{CaptureOptions{MaxWidth: 80}, image.Rect(0, 0, 80, 40)},
+		{CaptureOptions{MaxWidth: 80, MaxHeight: 20}, image.Rect(0, 0, 40, 20)},
+		{CaptureOptions{MaxWidth: 400}, image.Rect(0, 0, 200, 100)},
+		{CaptureOptions{Scale: 0.5, MaxHeight: 25, Filter: ResampleBilinear}, image.Rect(0, 0, 50, 25)},
+	} {
+		img, err := CaptureMonitorWithOptions(m, c.opts)
+		if err != nil {
+			t.Fatalf("%+v: CaptureMonitorWithOptions failed: %v", c.opts, err)
+		}
+		if img.Bounds() != c.want {
+			t.Errorf("%+v: bounds = %v, want %v", c.opts, img.Bounds(), c.want)
+		}
+	}
+}
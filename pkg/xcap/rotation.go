@@ -0,0 +1,119 @@
+package xcap
+
+import (
+	"image"
+	"math"
+)
+
+// Orientation 显示器截图的输出方向
+type Orientation int
+
+const (
+	// OrientationVisual 与屏幕上看到的画面方向一致（系统截图的默认方向）
+	OrientationVisual Orientation = iota
+	// OrientationFramebuffer 显示器帧缓冲（面板扫描）方向，即把画面按 Monitor.Rotation 逆向转回
+	OrientationFramebuffer
+)
+
+// String 返回方向名称
+func (o Orientation) String() string {
+	switch o {
+	case OrientationVisual:
+		return "visual"
+	case OrientationFramebuffer:
+		return "framebuffer"
+	default:
+		return "unknown"
+	}
+}
+
+// Rotate90 返回 img 顺时针旋转 90 度后的新图像
+func Rotate90(img *image.RGBA) *image.RGBA {
+	return rotateQuarters(img, 1)
+}
+
+// Rotate180 返回 img 旋转 180 度后的新图像
+func Rotate180(img *image.RGBA) *image.RGBA {
+	return rotateQuarters(img, 2)
+}
+
+// Rotate270 返回 img 顺时针旋转 270 度（逆时针 90 度）后的新图像
+func Rotate270(img *image.RGBA) *image.RGBA {
+	return rotateQuarters(img, 3)
+}
+
+// FlipHorizontal 返回 img 左右镜像后的新图像
+func FlipHorizontal(img *image.RGBA) *image.RGBA {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		src := img.Pix[img.PixOffset(b.Min.X, b.Min.Y+y):]
+		row := dst.Pix[y*dst.Stride:]
+		for x := 0; x < w; x++ {
+			copy(row[(w-1-x)*4:(w-x)*4], src[x*4:x*4+4])
+		}
+	}
+	return dst
+}
+
+// FlipVertical 返回 img 上下镜像后的新图像
+func FlipVertical(img *image.RGBA) *image.RGBA {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		src := img.Pix[img.PixOffset(b.Min.X, b.Min.Y+y):]
+		copy(dst.Pix[(h-1-y)*dst.Stride:], src[:w*4])
+	}
+	return dst
+}
+
+// rotateQuarters 把 img 顺时针旋转 n 个 90 度，结果的 Bounds 从 (0,0) 开始
+func rotateQuarters(img *image.RGBA, n int) *image.RGBA {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	n = ((n % 4) + 4) % 4
+
+	dw, dh := w, h
+	if n%2 == 1 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < h; y++ {
+		src := img.Pix[img.PixOffset(b.Min.X, b.Min.Y+y):]
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch n {
+			case 0:
+				dx, dy = x, y
+			case 1:
+				dx, dy = h-1-y, x
+			case 2:
+				dx, dy = w-1-x, h-1-y
+			case 3:
+				dx, dy = y, w-1-x
+			}
+			i := dst.PixOffset(dx, dy)
+			copy(dst.Pix[i:i+4], src[x*4:x*4+4])
+		}
+	}
+	return dst
+}
+
+// rotationQuarters 把以度为单位的旋转角度换算为顺时针 90 度的个数，非 90 的倍数按最近值取整
+func rotationQuarters(degrees float32) int {
+	n := int(math.Round(float64(degrees) / 90))
+	return ((n % 4) + 4) % 4
+}
+
+// toFramebuffer 把可视方向的截图转换为帧缓冲方向
+// Monitor.Rotation 是画面相对帧缓冲的顺时针角度，这里逆时针转回
+func toFramebuffer(img *image.RGBA, rotation float32) *image.RGBA {
+	n := rotationQuarters(rotation)
+	if n == 0 {
+		return img
+	}
+	return rotateQuarters(img, 4-n)
+}
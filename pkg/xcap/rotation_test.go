@@ -0,0 +1,116 @@
+package xcap
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// markedImage 返回 3x2 图像，每个像素颜色编码其坐标，便于检查像素去向
+func markedImage() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 3; x++ {
+			img.SetRGBA(x, y, color.RGBA{uint8(x), uint8(y), 0, 255})
+		}
+	}
+	return img
+}
+
+// checkMarks 检查 dst 的每个像素来自 src 中 want(x, y) 给出的坐标
+func checkMarks(t *testing.T, name string, dst *image.RGBA, w, h int, want func(x, y int) (int, int)) {
+	t.Helper()
+	if dst.Bounds() != image.Rect(0, 0, w, h) {
+		t.Fatalf("%s: bounds = %v, want %dx%d", name, dst.Bounds(), w, h)
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			sx, sy := want(x, y)
+			if got := dst.RGBAAt(x, y); got != (color.RGBA{uint8(sx), uint8(sy), 0, 255}) {
+				t.Errorf("%s: pixel (%d,%d) = %v, want from (%d,%d)", name, x, y, got, sx, sy)
+			}
+		}
+	}
+}
+
+func TestRotateAndFlip(t *testing.T) {
+	img := markedImage()
+
+	// 顺时针 90 度：目标 (x, y) 来自源 (y, h-1-x)
+	checkMarks(t, "Rotate90", Rotate90(img), 2, 3, func(x, y int) (int, int) { return y, 1 - x })
+	checkMarks(t, "Rotate180", Rotate180(img), 3, 2, func(x, y int) (int, int) { return 2 - x, 1 - y })
+	checkMarks(t, "Rotate270", Rotate270(img), 2, 3, func(x, y int) (int, int) { return 2 - y, x })
+	checkMarks(t, "FlipHorizontal", FlipHorizontal(img), 3, 2, func(x, y int) (int, int) { return 2 - x, y })
+	checkMarks(t, "FlipVertical", FlipVertical(img), 3, 2, func(x, y int) (int, int) { return x, 1 - y })
+
+	// 四次 90 度回到原图，源图不被修改
+	back := Rotate90(Rotate90(Rotate90(Rotate90(img))))
+	checkMarks(t, "Rotate90x4", back, 3, 2, func(x, y int) (int, int) { return x, y })
+	checkMarks(t, "source", img, 3, 2, func(x, y int) (int, int) { return x, y })
+}
+
+func TestRotateSubImage(t *testing.T) {
+	// 非零原点的子图像，结果从 (0,0) 开始
+	big := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	fillRect(big, image.Rect(5, 5, 8, 7), color.RGBA{0, 0, 0, 255})
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 3; x++ {
+			big.SetRGBA(5+x, 5+y, color.RGBA{uint8(x), uint8(y), 0, 255})
+		}
+	}
+	sub := big.SubImage(image.Rect(5, 5, 8, 7)).(*image.RGBA)
+
+	checkMarks(t, "Rotate90", Rotate90(sub), 2, 3, func(x, y int) (int, int) { return y, 1 - x })
+	checkMarks(t, "FlipHorizontal", FlipHorizontal(sub), 3, 2, func(x, y int) (int, int) { return 2 - x, y })
+	checkMarks(t, "FlipVertical", FlipVertical(sub), 3, 2, func(x, y int) (int, int) { return x, 1 - y })
+}
+
+func TestCaptureMonitorOrientation(t *testing.T) {
+	// 顺时针旋转 90 度的竖屏：可视方向 2x3，帧缓冲方向 3x2
+	visual := Rotate90(markedImage())
+	m := &fakeMonitor{id: 1, width: 2, height: 3, scaleFactor: 1, rotation: 90, img: visual}
+
+	img, err := CaptureMonitorWithOptions(m, CaptureOptions{})
+	if err != nil {
+		t.Fatalf("capture failed: %v", err)
+	}
+	checkMarks(t, "visual", img, 2, 3, func(x, y int) (int, int) { return y, 1 - x })
+
+	img, err = CaptureMonitorWithOptions(m, CaptureOptions{Orientation: OrientationFramebuffer})
+	if err != nil {
+		t.Fatalf("capture failed: %v", err)
+	}
+	checkMarks(t, "framebuffer", img, 3, 2, func(x, y int) (int, int) { return x, y })
+
+	// 未旋转的显示器两种方向相同
+	m.rotation = 0
+	m.img = markedImage()
+	img, err = CaptureMonitorWithOptions(m, CaptureOptions{Orientation: OrientationFramebuffer})
+	if err != nil {
+		t.Fatalf("capture failed: %v", err)
+	}
+	checkMarks(t, "unrotated", img, 3, 2, func(x, y int) (int, int) { return x, y })
+}
+
+func TestRotationQuarters(t *testing.T) {
+	tests := []struct {
+		degrees float32
+		want    int
+	}{
+		{0, 0}, {90, 1}, {180, 2}, {270, 3}, {360, 0}, {-90, 3}, {89.6, 1},
+	}
+	for _, tt := range tests {
+		if got := rotationQuarters(tt.degrees); got != tt.want {
+			t.Errorf("rotationQuarters(%v) = %d, want %d", tt.degrees, got, tt.want)
+		}
+	}
+}
+
+func TestOrientationString(t *testing.T) {
+	if OrientationVisual.String() != "visual" || OrientationFramebuffer.String() != "framebuffer" {
+		t.Error("unexpected orientation names")
+	}
+	if Orientation(9).String() != "unknown" {
+		t.Error("expected unknown for invalid orientation")
+	}
+}
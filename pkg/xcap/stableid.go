@@ -0,0 +1,120 @@
+package xcap
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MonitorByStableID 返回 StableID 为 id 的显示器，找不到时返回 ErrNoMonitor
+func MonitorByStableID(id string) (Monitor, error) {
+	monitors, err := AllMonitors()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, sid := range stableIDs(monitors) {
+		if sid == id {
+			return monitors[i], nil
+		}
+	}
+	return nil, ErrNoMonitor
+}
+
+// monitorStableID 在当前显示器集合中计算 m 的 StableID
+// 需要集合中其他显示器的信息才能处理冲突，见 Monitor.StableID
+func monitorStableID(m Monitor) string {
+	monitors, err := AllMonitors()
+	if err == nil {
+		ids := stableIDs(monitors)
+		for i, other := range monitors {
+			if other.ID() == m.ID() {
+				return ids[i]
+			}
+		}
+	}
+
+	// 枚举失败或 m 已断开，只能不考虑冲突
+	return stableIDs([]Monitor{m})[0]
+}
+
+// stableIDs 为一组显示器计算 StableID，结果与 monitors 一一对应
+func stableIDs(monitors []Monitor) []string {
+	ids := make([]string, len(monitors))
+	positioned := make([]bool, len(monitors))
+	for i, m := range monitors {
+		ids[i], positioned[i] = baseStableID(m)
+	}
+
+	// 序列号相同（如固件写死了序列号）时追加位置
+	for _, group := range duplicateIDs(ids) {
+		for _, i := range group {
+			if !positioned[i] {
+				ids[i] += fmt.Sprintf("@%d,%d", monitors[i].X(), monitors[i].Y())
+			}
+		}
+	}
+
+	// 位置也相同（镜像显示）时按枚举顺序编号，这种情况下 ID 不稳定
+	for _, group := range duplicateIDs(ids) {
+		for n, i := range group {
+			ids[i] += "#" + strconv.Itoa(n+1)
+		}
+	}
+	return ids
+}
+
+// baseStableID 根据显示器自身的信息生成 ID，不考虑与其他显示器的冲突
+// positioned 表示 ID 中已经包含位置
+func baseStableID(m Monitor) (id string, positioned bool) {
+	info, err := m.Info()
+	if err != nil {
+		info = &MonitorInfo{}
+	}
+
+	// 没有 EDID 厂商信息时（虚拟显示器等）用型号名称代替
+	vendor := fmt.Sprintf("%s:%04X", info.Manufacturer, info.ProductCode)
+	if info.Manufacturer == "" {
+		vendor = info.Model
+		if vendor == "" {
+			vendor = m.Name()
+		}
+	}
+
+	if info.SerialNumber != "" && !placeholderSerialString(info.SerialNumber) {
+		return vendor + ":" + info.SerialNumber, false
+	}
+	return fmt.Sprintf("%s:%s@%d,%d", vendor, info.Connector, m.X(), m.Y()), true
+}
+
+// placeholderSerialString 判断序列号描述符中的文本是否为固件写死的占位值
+// 这类序列号在同型号的显示器之间相同，按没有序列号处理，ID 总是包含位置，
+// 不会因为同型号的另一台显示器接入或断开而变化
+func placeholderSerialString(s string) bool {
+	switch strings.ToUpper(s) {
+	case "0123456789", "123456789", "1234567890", "SERIAL", "SERIALNUMBER", "SERIAL NUMBER", "SN", "NONE", "N/A":
+		return true
+	}
+	// 全部由同一个字符组成，如 "0000000000"、"11111111"
+	return strings.Count(s, s[:1]) == len(s)
+}
+
+// duplicateIDs 返回重复 ID 的下标分组，组内按下标升序
+func duplicateIDs(ids []string) [][]int {
+	groups := make(map[string][]int, len(ids))
+	var order []string
+	for i, id := range ids {
+		if _, ok := groups[id]; !ok {
+			order = append(order, id)
+		}
+		groups[id] = append(groups[id], i)
+	}
+
+	var dupes [][]int
+	for _, id := range order {
+		if len(groups[id]) > 1 {
+			dupes = append(dupes, groups[id])
+		}
+	}
+	return dupes
+}
@@ -0,0 +1,125 @@
+package xcap
+
+import (
+	"errors"
+	"testing"
+)
+
+// serialEDID 返回带序列号描述符的 EDID；serial 为空时不提供任何序列号
+func serialEDID(serial string) []byte {
+	b := edidBuilder{
+		vendor: "DEL", product: 0xa0ba,
+		version: 1, revision: 4, input: 0x80 | 0x20 | 0x02,
+		descriptors: map[byte]string{0xfc: "DELL U2415"},
+	}
+	if serial != "" {
+		b.descriptors[0xff] = serial
+	}
+	return b.build()
+}
+
+func TestStableIDWithSerial(t *testing.T) {
+	left := &fakeMonitor{id: 1, name: "DELL U2415", edid: serialEDID("AAA111")}
+	right := &fakeMonitor{id: 2, name: "DELL U2415", x: 1920, edid: serialEDID("BBB222")}
+	useFakeBackend(t, &fakeBackend{monitors: []Monitor{left, right}})
+
+	if got := left.StableID(); got != "DEL:A0BA:AAA111" {
+		t.Errorf("left = %q", got)
+	}
+	first := right.StableID()
+
+	// 重新连接后 ID() 和位置都变了，StableID 不变
+	moved := &fakeMonitor{id: 77, name: "DELL U2415", x: -1920, edid: serialEDID("BBB222")}
+	useFakeBackend(t, &fakeBackend{monitors: []Monitor{moved, left}})
+	if got := moved.StableID(); got != first {
+		t.Errorf("after reconnect = %q, want %q", got, first)
+	}
+
+	m, err := MonitorByStableID(first)
+	if err != nil || m.ID() != 77 {
+		t.Errorf("MonitorByStableID = %v, %v", m, err)
+	}
+	if _, err := MonitorByStableID("DEL:A0BA:missing"); !errors.Is(err, ErrNoMonitor) {
+		t.Errorf("expected ErrNoMonitor, got %v", err)
+	}
+}
+
+func TestStableIDWithoutSerial(t *testing.T) {
+	// 两台没有序列号的同型号显示器用接口和位置区分
+	a := &fakeMonitor{id: 1, edid: serialEDID("")}
+	b := &fakeMonitor{id: 2, x: 1920, edid: serialEDID("")}
+	useFakeBackend(t, &fakeBackend{monitors: []Monitor{a, b}})
+
+	if got := a.StableID(); got != "DEL:A0BA:hdmi@0,0" {
+		t.Errorf("a = %q", got)
+	}
+	if got := b.StableID(); got != "DEL:A0BA:hdmi@1920,0" {
+		t.Errorf("b = %q", got)
+	}
+}
+
+func TestStableIDCollisions(t *testing.T) {
+	// 固件写死相同序列号：追加位置
+	a := &fakeMonitor{id: 1, edid: serialEDID("SAME")}
+	b := &fakeMonitor{id: 2, x: 1920, edid: serialEDID("SAME")}
+	// 镜像显示：位置也相同，按枚举顺序编号
+	c := &fakeMonitor{id: 3, y: 1080, edid: serialEDID("")}
+	d := &fakeMonitor{id: 4, y: 1080, edid: serialEDID("")}
+
+	ids := stableIDs([]Monitor{a, b, c, d})
+	want := []string{
+		"DEL:A0BA:SAME@0,0",
+		"DEL:A0BA:SAME@1920,0",
+		"DEL:A0BA:hdmi@0,1080#1",
+		"DEL:A0BA:hdmi@0,1080#2",
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Errorf("ids[%d] = %q, want %q", i, ids[i], want[i])
+		}
+	}
+}
+
+func TestStableIDPlaceholderSerial(t *testing.T) {
+	// 占位序列号总是使用位置，与同型号显示器是否连接无关
+	a := &fakeMonitor{id: 1, edid: serialEDID("0000000000")}
+	alone := stableIDs([]Monitor{a})[0]
+	if alone != "DEL:A0BA:hdmi@0,0" {
+		t.Errorf("placeholder serial = %q", alone)
+	}
+
+	b := &fakeMonitor{id: 2, x: 1920, edid: serialEDID("0000000000")}
+	if got := stableIDs([]Monitor{a, b})[0]; got != alone {
+		t.Errorf("ID changed when twin connected: %q, want %q", got, alone)
+	}
+
+	for s, want := range map[string]bool{
+		"0000000000":   true,
+		"1234567890":   true,
+		"Serial":       true,
+		"7MT0154S0ABL": false,
+		"AAA111":       false,
+	} {
+		if got := placeholderSerialString(s); got != want {
+			t.Errorf("placeholderSerialString(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestStableIDWithoutEDID(t *testing.T) {
+	m := &fakeMonitor{id: 5, name: "Virtual Display", x: 10, y: 20}
+	useFakeBackend(t, &fakeBackend{})
+
+	// 不在枚举结果中时仍能计算
+	if got := m.StableID(); got != "Virtual Display:unknown@10,20" {
+		t.Errorf("StableID = %q", got)
+	}
+}
+
+func TestPlaceholderSerialIgnored(t *testing.T) {
+	b := edidBuilder{vendor: "SAM", product: 0x0e0f, serial: placeholderSerial, version: 1, revision: 3}
+	info := newMonitorInfo(nativeMonitorInfo{edid: b.build()})
+	if info.SerialNumber != "" {
+		t.Errorf("SerialNumber = %q, want empty", info.SerialNumber)
+	}
+}
@@ -1,6 +1,11 @@
 package xcap
 
-import "strings"
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+)
 
 // SanitizeFilename 将字符串转换为安全的文件名
 // 移除或替换不安全的文件名字符
@@ -20,3 +25,19 @@ func SanitizeFilename(name string) string {
 	}
 	return result
 }
+
+// cropRGBA 复制 img 中区域 r 的像素，返回的图像 Bounds() 与 r 相同
+// r 必须位于 img.Bounds() 之内
+func cropRGBA(img *image.RGBA, r image.Rectangle) *image.RGBA {
+	dst := image.NewRGBA(r)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		copy(dst.Pix[dst.PixOffset(r.Min.X, y):dst.PixOffset(r.Max.X, y)],
+			img.Pix[img.PixOffset(r.Min.X, y):img.PixOffset(r.Max.X, y)])
+	}
+	return dst
+}
+
+// fillRect 用颜色 c 填充 img 中的区域 r，r 会被裁剪到图像范围内
+func fillRect(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	draw.Draw(img, r.Intersect(img.Bounds()), &image.Uniform{C: c}, image.Point{}, draw.Src)
+}
@@ -0,0 +1,91 @@
+package xcap
+
+import "image"
+
+// windowVisibleRegion 是 Window.VisibleRegion 的通用实现
+//
+// 重新枚举窗口，以当前的几何信息和 Z 顺序计算 w 未被前面窗口遮挡、
+// 且位于某个显示器内的部分。窗口按矩形处理，不考虑圆角和透明区域。
+// 窗口存在但不在枚举结果中时（隐藏、cloaked 或工具窗口）无法确定 Z 顺序，返回 ErrNotSupported
+func windowVisibleRegion(w Window) ([]image.Rectangle, error) {
+	visible, _, err := windowVisibility(w)
+	return visible, err
+}
+
+// windowVisibility 返回 w 的可见部分和重新枚举得到的窗口矩形
+// 窗口最小化时两者都为空
+func windowVisibility(w Window) ([]image.Rectangle, image.Rectangle, error) {
+	if minimized, err := w.IsMinimized(); err == nil && minimized {
+		return nil, image.Rectangle{}, nil
+	}
+
+	windows, err := AllWindows()
+	if err != nil {
+		return nil, image.Rectangle{}, err
+	}
+	monitors, err := AllMonitors()
+	if err != nil {
+		return nil, image.Rectangle{}, err
+	}
+
+	sorted := sortFrontToBack(windows)
+	rects := make([]image.Rectangle, len(sorted))
+	index := -1
+	for i, other := range sorted {
+		rects[i] = windowRect(other)
+		if other.ID() == w.ID() {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		// 枚举会过滤隐藏、cloaked 和工具窗口，只有直接查询也找不到时窗口才是已关闭
+		if _, err := platform.windowByID(w.ID()); err != nil {
+			return nil, image.Rectangle{}, err
+		}
+		return nil, image.Rectangle{}, ErrNotSupported
+	}
+
+	parts := []image.Rectangle{rects[index]}
+	for _, front := range rects[:index] {
+		parts = subtractRects(parts, front)
+	}
+
+	// 只保留显示器内的部分；显示器可能互相重叠（如镜像），先拆成互不相交的矩形
+	screens := make([]image.Rectangle, len(monitors))
+	for i, m := range monitors {
+		screens[i] = monitorRect(m)
+	}
+
+	var visible []image.Rectangle
+	for _, screen := range visibleRegions(screens) {
+		for _, s := range screen {
+			for _, p := range parts {
+				if in := p.Intersect(s); !in.Empty() {
+					visible = append(visible, in)
+				}
+			}
+		}
+	}
+
+	return visible, rects[index], nil
+}
+
+// windowIsOccluded 是 Window.IsOccluded 的通用实现
+// 与重新枚举得到的窗口矩形比较，窗口在枚举后移动或改变大小时结果仍然正确
+func windowIsOccluded(w Window) (bool, error) {
+	visible, rect, err := windowVisibility(w)
+	if err != nil {
+		return false, err
+	}
+	if rect.Empty() {
+		// 最小化或没有面积
+		return true, nil
+	}
+
+	area := 0
+	for _, r := range visible {
+		area += r.Dx() * r.Dy()
+	}
+	return area < rect.Dx()*rect.Dy(), nil
+}
@@ -0,0 +1,79 @@
+package xcap
+
+import (
+	"errors"
+	"image"
+	"testing"
+)
+
+func TestWindowVisibleRegion(t *testing.T) {
+	monitor := &fakeMonitor{width: 100, height: 100}
+	top := &fakeWindow{id: 1, x: 0, y: 0, z: 3, width: 50, height: 100}
+	middle := &fakeWindow{id: 2, x: 30, y: 20, z: 2, width: 40, height: 40}
+	// 右侧超出显示器
+	bottom := &fakeWindow{id: 3, x: 80, y: 80, z: 1, width: 40, height: 40}
+	hidden := &fakeWindow{id: 4, x: 10, y: 10, z: 0, width: 20, height: 20}
+	minimized := &fakeWindow{id: 5, x: 60, y: 60, width: 10, height: 10, minimized: true}
+	// 存在但被枚举过滤掉的工具窗口
+	tool := &fakeWindow{id: 6, x: 0, y: 0, width: 10, height: 10}
+
+	useFakeBackend(t, &fakeBackend{
+		monitors: []Monitor{monitor},
+		windows:  []Window{bottom, top, hidden, middle},
+		hidden:   []Window{tool},
+	})
+
+	for _, c := range []struct {
+		w        *fakeWindow
+		bounds   image.Rectangle
+		area     int
+		occluded bool
+	}{
+		{top, image.Rect(0, 0, 50, 100), 50 * 100, false},
+		{middle, image.Rect(50, 20, 70, 60), 20 * 40, true},
+		{bottom, image.Rect(80, 80, 100, 100), 20 * 20, true},
+		{hidden, image.Rectangle{}, 0, true},
+	} {
+		visible, err := c.w.VisibleRegion()
+		if err != nil {
+			t.Fatalf("window %d: VisibleRegion failed: %v", c.w.id, err)
+		}
+		area := 0
+		for _, r := range visible {
+			area += r.Dx() * r.Dy()
+		}
+		if got := boundingRect(visible); got != c.bounds || area != c.area {
+			t.Errorf("window %d: visible bounds %v area %d, want %v area %d", c.w.id, got, area, c.bounds, c.area)
+		}
+
+		occluded, err := c.w.IsOccluded()
+		if err != nil {
+			t.Fatalf("window %d: IsOccluded failed: %v", c.w.id, err)
+		}
+		if occluded != c.occluded {
+			t.Errorf("window %d: IsOccluded = %v, want %v", c.w.id, occluded, c.occluded)
+		}
+	}
+
+	// 枚举后窗口变小了：与当前的窗口矩形比较，而不是旧的尺寸
+	stale := &fakeWindow{id: top.id, width: 200, height: 200}
+	if occluded, err := stale.IsOccluded(); err != nil || occluded {
+		t.Errorf("resized window: IsOccluded = %v, %v; want false", occluded, err)
+	}
+	if occluded, err := minimized.IsOccluded(); err != nil || !occluded {
+		t.Errorf("minimized window: IsOccluded = %v, %v; want true", occluded, err)
+	}
+
+	if visible, err := minimized.VisibleRegion(); err != nil || len(visible) != 0 {
+		t.Errorf("minimized window: VisibleRegion = %v, %v; want empty", visible, err)
+	}
+
+	if _, err := tool.VisibleRegion(); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("filtered window: err = %v, want ErrNotSupported", err)
+	}
+
+	closed := &fakeWindow{id: 99, width: 10, height: 10}
+	if _, err := closed.VisibleRegion(); !errors.Is(err, ErrWindowClosed) {
+		t.Errorf("closed window: err = %v, want ErrWindowClosed", err)
+	}
+}
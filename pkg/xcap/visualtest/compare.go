@@ -0,0 +1,212 @@
+package visualtest
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// 差异图中使用的颜色
+var (
+	diffColor = color.RGBA{255, 0, 0, 255}   // 超出容差的像素
+	aaColor   = color.RGBA{255, 255, 0, 255} // 被识别为抗锯齿而忽略的像素
+)
+
+// Options 控制图像比较的方式
+type Options struct {
+	// Tolerance 每个通道允许的最大差值（0-255）
+	Tolerance uint8
+
+	// IgnoreMasks 不参与比较的区域（如时间、光标等易变内容），坐标相对图像 Bounds
+	IgnoreMasks []image.Rectangle
+
+	// AntiAliasing 为 true 时忽略被识别为抗锯齿边缘的差异
+	// 字体和圆角在不同机器上的抗锯齿结果常有细微差别
+	AntiAliasing bool
+
+	// MaxDiffPixels 允许的最大差异像素数，超过时比较失败
+	MaxDiffPixels int
+}
+
+// Result 是一次比较的结果
+type Result struct {
+	// Passed 比较是否通过：尺寸一致且差异像素数不超过 MaxDiffPixels
+	Passed bool
+
+	// SizeMismatch 两张图尺寸不同，此时其他字段无意义
+	SizeMismatch bool
+
+	// DiffPixels 超出容差的像素数
+	DiffPixels int
+
+	// AntiAliasedPixels 因抗锯齿而被忽略的像素数
+	AntiAliasedPixels int
+
+	// TotalPixels 参与比较的像素总数（不含 IgnoreMasks）
+	TotalPixels int
+
+	// Diff 差异图：以淡化的期望图为底，差异像素标为红色，忽略的抗锯齿像素标为黄色
+	Diff *image.RGBA
+}
+
+// Compare 逐像素比较 got 与 want
+func Compare(got, want image.Image, opts Options) *Result {
+	if got.Bounds().Size() != want.Bounds().Size() {
+		return &Result{SizeMismatch: true}
+	}
+
+	a := toRGBA(got)
+	b := toRGBA(want)
+	w, h := a.Bounds().Dx(), a.Bounds().Dy()
+
+	res := &Result{Diff: fadedCopy(b)}
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if masked(opts.IgnoreMasks, x, y) {
+				continue
+			}
+			res.TotalPixels++
+
+			if pixelEqual(a, b, x, y, opts.Tolerance) {
+				continue
+			}
+
+			if opts.AntiAliasing && (antiAliased(a, b, x, y) || antiAliased(b, a, x, y)) {
+				res.AntiAliasedPixels++
+				res.Diff.SetRGBA(x, y, aaColor)
+				continue
+			}
+
+			res.DiffPixels++
+			res.Diff.SetRGBA(x, y, diffColor)
+		}
+	}
+
+	res.Passed = res.DiffPixels <= opts.MaxDiffPixels
+	return res
+}
+
+// toRGBA 将任意图像转换为 Bounds 从 (0,0) 开始的 *image.RGBA
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Bounds().Min == (image.Point{}) {
+		return rgba
+	}
+	b := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, b.Min, draw.Src)
+	return rgba
+}
+
+// fadedCopy 返回淡化为浅灰的副本，作为差异图的底图
+func fadedCopy(img *image.RGBA) *image.RGBA {
+	out := image.NewRGBA(img.Bounds())
+	for i := 0; i < len(img.Pix); i += 4 {
+		l := luma(img.Pix[i], img.Pix[i+1], img.Pix[i+2])
+		v := uint8(255 - (255-l)/4)
+		out.Pix[i+0] = v
+		out.Pix[i+1] = v
+		out.Pix[i+2] = v
+		out.Pix[i+3] = 255
+	}
+	return out
+}
+
+func masked(masks []image.Rectangle, x, y int) bool {
+	p := image.Pt(x, y)
+	for _, m := range masks {
+		if p.In(m) {
+			return true
+		}
+	}
+	return false
+}
+
+func pixelEqual(a, b *image.RGBA, x, y int, tol uint8) bool {
+	pa := a.Pix[a.PixOffset(x, y):]
+	pb := b.Pix[b.PixOffset(x, y):]
+	for i := 0; i < 4; i++ {
+		d := int(pa[i]) - int(pb[i])
+		if d > int(tol) || -d > int(tol) {
+			return false
+		}
+	}
+	return true
+}
+
+func luma(r, g, b uint8) uint8 {
+	return uint8((299*int(r) + 587*int(g) + 114*int(b)) / 1000)
+}
+
+func lumaAt(img *image.RGBA, x, y int) int {
+	p := img.Pix[img.PixOffset(x, y):]
+	return int(luma(p[0], p[1], p[2]))
+}
+
+// antiAliased 判断 img 中 (x, y) 是否像抗锯齿边缘像素（参考 pixelmatch 的判定方法）：
+// 周围同时存在更亮和更暗的像素，且最亮或最暗的邻居位于两张图中都平坦的区域内
+func antiAliased(img, other *image.RGBA, x, y int) bool {
+	b := img.Bounds()
+	center := lumaAt(img, x, y)
+
+	equal := 0
+	minDelta, maxDelta := 0, 0
+	var minX, minY, maxX, maxY int
+
+	for ny := y - 1; ny <= y+1; ny++ {
+		for nx := x - 1; nx <= x+1; nx++ {
+			if (nx == x && ny == y) || !image.Pt(nx, ny).In(b) {
+				continue
+			}
+
+			delta := lumaAt(img, nx, ny) - center
+			switch {
+			case delta == 0:
+				equal++
+				// 与中心相同的邻居过多说明不是边缘
+				if equal > 2 {
+					return false
+				}
+			case delta < minDelta:
+				minDelta, minX, minY = delta, nx, ny
+			case delta > maxDelta:
+				maxDelta, maxX, maxY = delta, nx, ny
+			}
+		}
+	}
+
+	// 必须同时存在更暗和更亮的邻居
+	if minDelta == 0 || maxDelta == 0 {
+		return false
+	}
+
+	return (hasManySiblings(img, minX, minY) && hasManySiblings(other, minX, minY)) ||
+		(hasManySiblings(img, maxX, maxY) && hasManySiblings(other, maxX, maxY))
+}
+
+// hasManySiblings 判断 (x, y) 周围是否至少有 3 个颜色完全相同的邻居
+func hasManySiblings(img *image.RGBA, x, y int) bool {
+	b := img.Bounds()
+	c := img.RGBAAt(x, y)
+
+	// 位于图像边缘的像素邻居较少，预先计入一部分
+	zeroes := 0
+	if x == b.Min.X || x == b.Max.X-1 || y == b.Min.Y || y == b.Max.Y-1 {
+		zeroes = 1
+	}
+
+	for ny := y - 1; ny <= y+1; ny++ {
+		for nx := x - 1; nx <= x+1; nx++ {
+			if (nx == x && ny == y) || !image.Pt(nx, ny).In(b) {
+				continue
+			}
+			if img.RGBAAt(nx, ny) == c {
+				zeroes++
+			}
+			if zeroes > 2 {
+				return true
+			}
+		}
+	}
+	return false
+}
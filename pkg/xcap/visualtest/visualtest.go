@@ -0,0 +1,193 @@
+// Package visualtest 提供基于黄金图（golden image）的视觉回归测试工具。
+//
+// 典型用法是在 CI 中截取被测应用的窗口，与仓库中保存的黄金图比较：
+//
+//	func TestMainWindow(t *testing.T) {
+//	    img, _, err := visualtest.CaptureWindow(visualtest.Filter{
+//	        AppName: "MyApp",
+//	        Title:   regexp.MustCompile(`^Main`),
+//	    })
+//	    if err != nil {
+//	        t.Fatal(err)
+//	    }
+//	    visualtest.AssertGolden(t, "main_window", img, visualtest.Options{
+//	        Tolerance:    8,
+//	        AntiAliasing: true,
+//	        IgnoreMasks:  []image.Rectangle{image.Rect(0, 0, 200, 30)},
+//	    })
+//	}
+//
+// 设置环境变量 XCAP_UPDATE_GOLDEN=1 运行测试可以重新生成黄金图；
+// 测试包自己定义了布尔参数 -update 时，`go test -update` 同样生效。
+// 本包不注册命令行参数。比较失败时，差异图（红色标出差异像素）
+// 和实际截图会写在黄金图旁边，便于作为 CI 产物上传。
+package visualtest
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/zn-chen/xcap/pkg/xcap"
+)
+
+// DefaultGoldenDir 是黄金图的默认目录（相对于测试包目录）
+const DefaultGoldenDir = "testdata"
+
+// UpdateEnv 是开启黄金图更新的环境变量，值为 1 时生效
+const UpdateEnv = "XCAP_UPDATE_GOLDEN"
+
+// updateGolden 判断是否要重新生成黄金图
+// 库不注册全局参数，只在测试包定义了 -update 时读取它的值
+func updateGolden() bool {
+	if os.Getenv(UpdateEnv) == "1" {
+		return true
+	}
+	f := flag.Lookup("update")
+	return f != nil && f.Value.String() == "true"
+}
+
+// Filter 描述要截取的窗口，所有非零字段都必须匹配
+type Filter struct {
+	// AppName 应用程序名称，完全匹配
+	AppName string
+
+	// Title 窗口标题正则表达式
+	Title *regexp.Regexp
+
+	// PID 所属进程 ID
+	PID uint32
+}
+
+// Match 判断窗口是否满足过滤条件
+func (f Filter) Match(w xcap.Window) bool {
+	if f.AppName != "" && w.AppName() != f.AppName {
+		return false
+	}
+	if f.Title != nil && !f.Title.MatchString(w.Title()) {
+		return false
+	}
+	if f.PID != 0 && w.PID() != f.PID {
+		return false
+	}
+	return true
+}
+
+// CaptureWindow 截取第一个满足过滤条件的窗口
+// 没有匹配的窗口时返回 xcap.ErrNoWindow
+func CaptureWindow(f Filter) (*image.RGBA, xcap.Window, error) {
+	windows, err := xcap.AllWindows()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, w := range windows {
+		if !f.Match(w) {
+			continue
+		}
+		img, err := w.CaptureImage()
+		if err != nil {
+			return nil, w, err
+		}
+		return img, w, nil
+	}
+
+	return nil, nil, xcap.ErrNoWindow
+}
+
+// Golden 描述一张黄金图及其比较参数
+type Golden struct {
+	// Dir 黄金图所在目录，为空时使用 DefaultGoldenDir
+	Dir string
+
+	// Update 为 true 时用实际图像覆盖黄金图
+	// AssertGolden 在设置了 XCAP_UPDATE_GOLDEN=1 或传入测试包定义的 -update 时自动开启
+	Update bool
+
+	Options
+}
+
+// Path 返回名为 name 的黄金图路径
+func (g Golden) Path(name string) string {
+	dir := g.Dir
+	if dir == "" {
+		dir = DefaultGoldenDir
+	}
+	return filepath.Join(dir, name+".png")
+}
+
+// Check 将 got 与黄金图 name 比较
+// 比较失败时在黄金图旁写出 <name>.diff.png 和 <name>.actual.png，并返回描述失败的错误
+func (g Golden) Check(name string, got image.Image) (*Result, error) {
+	path := g.Path(name)
+
+	if g.Update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, err
+		}
+		return &Result{Passed: true}, writePNG(path, got)
+	}
+
+	want, err := readPNG(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read golden image (run with %s=1 to create it): %w", UpdateEnv, err)
+	}
+
+	res := Compare(got, want, g.Options)
+	if res.Passed {
+		return res, nil
+	}
+
+	base := path[:len(path)-len(".png")]
+	if err := writePNG(base+".actual.png", got); err != nil {
+		return res, err
+	}
+
+	if res.SizeMismatch {
+		return res, fmt.Errorf("%s: size mismatch: got %v, want %v (actual: %s.actual.png)",
+			name, got.Bounds().Size(), want.Bounds().Size(), base)
+	}
+
+	if err := writePNG(base+".diff.png", res.Diff); err != nil {
+		return res, err
+	}
+	return res, fmt.Errorf("%s: %d of %d pixels differ (max %d, %d anti-aliased ignored), diff: %s.diff.png",
+		name, res.DiffPixels, res.TotalPixels, g.MaxDiffPixels, res.AntiAliasedPixels, base)
+}
+
+// AssertGolden 将 got 与 testdata/<name>.png 比较，不一致时报告测试失败
+// 设置了 XCAP_UPDATE_GOLDEN=1 或传入 -update 时改为重新生成黄金图
+func AssertGolden(t testing.TB, name string, got image.Image, opts Options) {
+	t.Helper()
+
+	g := Golden{Update: updateGolden(), Options: opts}
+	if _, err := g.Check(name, got); err != nil {
+		t.Error(err)
+	}
+}
+
+func readPNG(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
+
+func writePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
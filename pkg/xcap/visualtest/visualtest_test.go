@@ -0,0 +1,144 @@
+package visualtest
+
+import (
+	"flag"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newImage(w, h int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+// 使用本包的测试包可以定义自己的 -update 参数
+var update = flag.Bool("update", false, "regenerate golden files")
+
+var (
+	white = color.RGBA{255, 255, 255, 255}
+	black = color.RGBA{0, 0, 0, 255}
+	gray  = color.RGBA{128, 128, 128, 255}
+)
+
+func TestCompareTolerance(t *testing.T) {
+	want := newImage(10, 10, gray)
+	got := newImage(10, 10, color.RGBA{132, 128, 128, 255})
+
+	if res := Compare(got, want, Options{Tolerance: 4}); !res.Passed || res.DiffPixels != 0 {
+		t.Errorf("within tolerance: passed=%v diff=%d, want pass", res.Passed, res.DiffPixels)
+	}
+	if res := Compare(got, want, Options{Tolerance: 3}); res.Passed || res.DiffPixels != 100 {
+		t.Errorf("above tolerance: passed=%v diff=%d, want fail with 100 pixels", res.Passed, res.DiffPixels)
+	}
+}
+
+func TestCompareIgnoreMasks(t *testing.T) {
+	want := newImage(20, 20, white)
+	got := newImage(20, 20, white)
+	got.SetRGBA(5, 5, black)
+	got.SetRGBA(15, 15, black)
+
+	res := Compare(got, want, Options{IgnoreMasks: []image.Rectangle{image.Rect(0, 0, 10, 10)}})
+	if res.DiffPixels != 1 {
+		t.Errorf("DiffPixels = %d, want 1", res.DiffPixels)
+	}
+	if res.TotalPixels != 300 {
+		t.Errorf("TotalPixels = %d, want 300", res.TotalPixels)
+	}
+	if c := res.Diff.RGBAAt(15, 15); c != diffColor {
+		t.Errorf("diff image pixel = %v, want red", c)
+	}
+}
+
+func TestCompareAntiAliasing(t *testing.T) {
+	// 左黑右白的竖直边缘，got 中边缘处多了一列灰色的抗锯齿像素
+	want := newImage(10, 10, white)
+	got := newImage(10, 10, white)
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 5; x++ {
+			want.SetRGBA(x, y, black)
+			got.SetRGBA(x, y, black)
+		}
+		got.SetRGBA(4, y, gray)
+	}
+
+	if res := Compare(got, want, Options{}); res.DiffPixels != 10 {
+		t.Fatalf("without AA detection: DiffPixels = %d, want 10", res.DiffPixels)
+	}
+
+	res := Compare(got, want, Options{AntiAliasing: true})
+	if !res.Passed {
+		t.Errorf("with AA detection: DiffPixels = %d, want 0", res.DiffPixels)
+	}
+	if res.AntiAliasedPixels != 10 {
+		t.Errorf("AntiAliasedPixels = %d, want 10", res.AntiAliasedPixels)
+	}
+
+	// 平坦区域中的孤立差异不是抗锯齿
+	got.SetRGBA(8, 5, gray)
+	if res := Compare(got, want, Options{AntiAliasing: true}); res.DiffPixels != 1 {
+		t.Errorf("isolated change: DiffPixels = %d, want 1", res.DiffPixels)
+	}
+}
+
+func TestCompareSizeMismatch(t *testing.T) {
+	res := Compare(newImage(10, 10, white), newImage(10, 11, white), Options{})
+	if res.Passed || !res.SizeMismatch {
+		t.Errorf("size mismatch: passed=%v sizeMismatch=%v", res.Passed, res.SizeMismatch)
+	}
+}
+
+func TestGoldenUpdateAndCheck(t *testing.T) {
+	dir := t.TempDir()
+	img := newImage(8, 8, gray)
+
+	// 黄金图不存在时失败
+	if _, err := (Golden{Dir: dir}).Check("button", img); err == nil {
+		t.Fatal("Check without golden file succeeded, want error")
+	}
+
+	if _, err := (Golden{Dir: dir, Update: true}).Check("button", img); err != nil {
+		t.Fatalf("Check with Update failed: %v", err)
+	}
+	if _, err := (Golden{Dir: dir}).Check("button", img); err != nil {
+		t.Fatalf("Check against fresh golden failed: %v", err)
+	}
+
+	changed := newImage(8, 8, gray)
+	changed.SetRGBA(3, 3, white)
+	if _, err := (Golden{Dir: dir}).Check("button", changed); err == nil {
+		t.Fatal("Check of changed image succeeded, want error")
+	}
+	for _, name := range []string{"button.diff.png", "button.actual.png"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected failure artifact %s: %v", name, err)
+		}
+	}
+}
+
+func TestUpdateGolden(t *testing.T) {
+	t.Setenv(UpdateEnv, "")
+	if updateGolden() {
+		t.Error("updateGolden() = true without env or flag")
+	}
+
+	t.Setenv(UpdateEnv, "1")
+	if !updateGolden() {
+		t.Errorf("updateGolden() = false with %s=1", UpdateEnv)
+	}
+
+	t.Setenv(UpdateEnv, "")
+	*update = true
+	defer func() { *update = false }()
+	if !updateGolden() {
+		t.Error("updateGolden() = false with -update")
+	}
+}
@@ -1,10 +1,15 @@
 package xcap
 
-import "image"
+import (
+	"context"
+	"errors"
+	"image"
+)
 
 // Window 表示一个应用程序窗口
 type Window interface {
 	// ID 返回窗口的唯一标识符（Windows 上为 HWND，macOS 上为 CGWindowID）
+	// 窗口存在期间不变，可以保存下来用 WindowByID 重新获取
 	ID() uint32
 
 	// PID 返回窗口所属进程的 ID
@@ -16,21 +21,34 @@ type Window interface {
 	// Title 返回窗口标题
 	Title() string
 
-	// X 返回窗口左上角的 x 坐标
+	// X 返回窗口左上角的全局 x 坐标
+	// 几何方法与 Monitor 相同，使用 NativeSpace() 坐标空间：macOS 为点，Windows 为物理像素
 	X() int
 
-	// Y 返回窗口左上角的 y 坐标
+	// Y 返回窗口左上角的全局 y 坐标（NativeSpace() 空间）
 	Y() int
 
 	// Z 返回窗口的 Z 顺序（值越大越靠前）
 	Z() int
 
-	// Width 返回窗口的宽度（像素）
+	// Width 返回窗口的宽度（NativeSpace() 空间）
 	Width() uint32
 
-	// Height 返回窗口的高度（像素）
+	// Height 返回窗口的高度（NativeSpace() 空间）
 	Height() uint32
 
+	// Bounds 返回窗口 area 区域的全局坐标（NativeSpace() 空间），每次调用都重新查询
+	// WindowAreaFrame 与 X/Y/Width/Height 相同；平台无法提供的区域返回 ErrNotSupported
+	Bounds(area WindowArea) (image.Rectangle, error)
+
+	// Refresh 重新读取窗口的标题、位置和尺寸，之后 Title()、X() 等返回最新的值
+	// 只查询这一个窗口，比重新枚举所有窗口开销小；Z() 保持枚举时的值。
+	// 窗口已关闭时返回 ErrWindowClosed，原有的值保持不变
+	Refresh() error
+
+	// Exists 返回窗口是否仍然存在，窗口已关闭时返回 false 且 error 为 nil
+	Exists() (bool, error)
+
 	// IsMinimized 返回窗口是否最小化
 	// 如果平台不支持该功能，返回 ErrNotSupported
 	IsMinimized() (bool, error)
@@ -43,9 +61,74 @@ type Window interface {
 	// 如果平台不支持该功能，返回 ErrNotSupported
 	IsFocused() (bool, error)
 
+	// Class 返回窗口类名（Windows 上为注册的窗口类，如 "Notepad"）
+	// macOS 没有窗口类，返回 ErrNotSupported
+	Class() (string, error)
+
+	// Type 返回窗口类型，用于区分主窗口和对话框、工具提示、面板等
+	// Windows 上 AllWindows 会过滤 WS_EX_TOOLWINDOW 窗口，枚举结果只会是普通窗口、对话框、
+	// 任务栏和桌面；工具提示、菜单和工具窗口只能通过 WindowByID 获取
+	Type() (WindowType, error)
+
+	// ProcessPath 返回所属进程的可执行文件路径
+	// 无权读取该进程时返回 ErrNotSupported
+	ProcessPath() (string, error)
+
+	// OwnerID 返回所有者窗口的 ID（如对话框所属的主窗口），没有所有者时返回 0
+	// macOS 上返回 ErrNotSupported
+	OwnerID() (uint32, error)
+
+	// ParentID 返回父窗口的 ID，顶层窗口返回 0
+	// 枚举只返回顶层窗口，因此 Windows 上总是 0；macOS 上返回 ErrNotSupported
+	ParentID() (uint32, error)
+
+	// IsModal 返回窗口是否为模态窗口
+	// Windows 上判断所有者窗口是否被禁用；macOS 上判断是否位于模态面板层级，无法识别工作表（sheet）
+	IsModal() (bool, error)
+
+	// Opacity 返回窗口的不透明度，0 为完全透明，1 为不透明
+	Opacity() (float64, error)
+
 	// CurrentMonitor 返回窗口所在的显示器
 	CurrentMonitor() (Monitor, error)
 
-	// CaptureImage 截取窗口内容，返回 RGBA 图像
+	// CaptureImage 截取窗口内容（WindowAreaFrame），返回物理像素的 RGBA 图像
+	// 其他区域使用 CaptureWindowWithOptions 和 CaptureOptions.WindowArea
+	// 窗口已关闭时返回 ErrWindowClosed
 	CaptureImage() (*image.RGBA, error)
+
+	// CaptureImageContext 与 CaptureImage 相同，但在 ctx 取消或超时时立即返回 ctx.Err()
+	// 被放弃的平台调用在工作线程上运行完毕后结果被丢弃，在此之前后续调用排队等待
+	CaptureImageContext(ctx context.Context) (*image.RGBA, error)
+
+	// VisibleRegion 返回窗口当前在屏幕上可见的部分（全局坐标，NativeSpace() 空间）
+	// 即未被前面的窗口遮挡、且位于显示器内的矩形，互不相交。
+	// 每次调用都会重新枚举窗口；窗口已关闭时返回 ErrWindowClosed，
+	// 窗口不在 AllWindows 的结果中（隐藏、cloaked 或工具窗口）时返回 ErrNotSupported
+	VisibleRegion() ([]image.Rectangle, error)
+
+	// IsOccluded 返回窗口是否有部分不可见（被其他窗口遮挡、超出屏幕或已最小化）
+	// 返回 false 时用户看到的内容与 CaptureImage 的结果一致
+	IsOccluded() (bool, error)
+}
+
+// WindowByID 返回 ID 为 id 的窗口
+//
+// 直接查询该窗口，不经过 AllWindows 的过滤，隐藏、cloaked 和工具窗口也能找到。
+// 窗口不存在时返回 ErrWindowClosed（同时匹配 ErrNoWindow）。
+// 单个窗口的查询没有 Z 顺序，Z() 返回 0；需要比较 Z 顺序时使用 AllWindows
+func WindowByID(id uint32) (Window, error) {
+	return platform.windowByID(id)
+}
+
+// windowExists 把查询窗口时的错误转换为 Window.Exists 的结果
+func windowExists(err error) (bool, error) {
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, ErrWindowClosed):
+		return false, nil
+	default:
+		return false, err
+	}
 }
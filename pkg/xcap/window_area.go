@@ -0,0 +1,85 @@
+package xcap
+
+import (
+	"context"
+	"image"
+	"math"
+)
+
+// WindowArea 选择窗口截图和 Window.Bounds 覆盖的区域
+type WindowArea int
+
+const (
+	// WindowAreaFrame 窗口框架，包括标题栏和边框，与 X/Y/Width/Height 一致
+	// 这是 Window.CaptureImage 截取的区域
+	WindowAreaFrame WindowArea = iota
+
+	// WindowAreaClient 客户区，即不含标题栏和边框的窗口内容
+	// macOS 不提供客户区的位置，返回 ErrNotSupported
+	WindowAreaClient
+
+	// WindowAreaFrameShadow 窗口框架加上系统绘制的阴影
+	// 只有 macOS 支持截图，由窗口服务器合成真实的阴影，但不提供阴影范围，Bounds 返回 ErrNotSupported；
+	// Windows 上 PrintWindow 不绘制阴影，截图和 Bounds 都返回 ErrNotSupported
+	WindowAreaFrameShadow
+)
+
+// String 返回窗口区域的名称
+func (a WindowArea) String() string {
+	switch a {
+	case WindowAreaFrame:
+		return "frame"
+	case WindowAreaClient:
+		return "client"
+	case WindowAreaFrameShadow:
+		return "frame+shadow"
+	default:
+		return "unknown"
+	}
+}
+
+// areaCapturer 由能够截取窗口框架以外区域的窗口实现
+type areaCapturer interface {
+	captureArea(ctx context.Context, area WindowArea) (*image.RGBA, error)
+}
+
+// captureWindowArea 截取窗口的 area 区域
+// 不支持按区域截图的窗口只能截取 WindowAreaFrame，其余区域返回 ErrNotSupported
+func captureWindowArea(w Window, area WindowArea) (*image.RGBA, error) {
+	if c, ok := w.(areaCapturer); ok {
+		return c.captureArea(context.Background(), area)
+	}
+	if area != WindowAreaFrame {
+		return nil, ErrNotSupported
+	}
+	return w.CaptureImage()
+}
+
+// cropWindowArea 从覆盖全局区域 captured 的窗口截图中裁剪出全局区域 area
+// 截图的像素尺寸与 captured 不同时按比例换算；area 与 captured 不相交时返回 ErrInvalidRegion
+func cropWindowArea(img *image.RGBA, captured, area image.Rectangle) (*image.RGBA, error) {
+	r := area.Intersect(captured)
+	if r.Empty() {
+		return nil, ErrInvalidRegion
+	}
+	if r == captured {
+		return img, nil
+	}
+
+	b := img.Bounds()
+	sx := float64(b.Dx()) / float64(captured.Dx())
+	sy := float64(b.Dy()) / float64(captured.Dy())
+	px := image.Rect(
+		int(math.Round(float64(r.Min.X-captured.Min.X)*sx)),
+		int(math.Round(float64(r.Min.Y-captured.Min.Y)*sy)),
+		int(math.Round(float64(r.Max.X-captured.Min.X)*sx)),
+		int(math.Round(float64(r.Max.Y-captured.Min.Y)*sy)),
+	).Add(b.Min).Intersect(b)
+	if px.Empty() {
+		return nil, ErrInvalidRegion
+	}
+
+	out := cropRGBA(img, px)
+	out.Rect = out.Rect.Sub(px.Min)
+	return out, nil
+}
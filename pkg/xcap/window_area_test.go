@@ -0,0 +1,62 @@
+package xcap
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestCaptureWindowArea(t *testing.T) {
+	w := &fakeWindow{id: 1, width: 40, height: 30, img: solidImage(40, 30, color.RGBA{R: 255, A: 255})}
+
+	img, err := CaptureWindowWithOptions(w, CaptureOptions{})
+	if err != nil || img.Bounds().Dx() != 40 {
+		t.Fatalf("frame capture = %v, %v", img.Bounds(), err)
+	}
+
+	// 不支持按区域截图的窗口只能截取框架
+	if _, err := CaptureWindowWithOptions(w, CaptureOptions{WindowArea: WindowAreaClient}); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("expected ErrNotSupported, got %v", err)
+	}
+}
+
+func TestCropWindowArea(t *testing.T) {
+	// 2x 截图：窗口矩形 (100,50)-(140,80) 对应 80x60 像素
+	captured := image.Rect(100, 50, 140, 80)
+	img := solidImage(80, 60, color.RGBA{A: 255})
+	client := image.Rect(104, 60, 136, 76)
+	blue := color.RGBA{B: 255, A: 255}
+	fillRect(img, image.Rect(8, 20, 72, 52), blue)
+
+	out, err := cropWindowArea(img, captured, client)
+	if err != nil {
+		t.Fatalf("cropWindowArea failed: %v", err)
+	}
+	if out.Bounds() != image.Rect(0, 0, 64, 32) {
+		t.Fatalf("bounds = %v", out.Bounds())
+	}
+	if out.RGBAAt(0, 0) != blue || out.RGBAAt(63, 31) != blue {
+		t.Errorf("corners = %v %v", out.RGBAAt(0, 0), out.RGBAAt(63, 31))
+	}
+
+	// 区域超出截图时裁剪到截图范围
+	out, err = cropWindowArea(img, captured, image.Rect(90, 40, 110, 60))
+	if err != nil || out.Bounds() != image.Rect(0, 0, 20, 20) {
+		t.Errorf("clipped = %v, %v", out.Bounds(), err)
+	}
+
+	// 最小化的窗口没有客户区
+	if _, err := cropWindowArea(img, captured, image.Rectangle{}); !errors.Is(err, ErrInvalidRegion) {
+		t.Errorf("expected ErrInvalidRegion, got %v", err)
+	}
+}
+
+func TestWindowAreaString(t *testing.T) {
+	if WindowAreaClient.String() != "client" || WindowAreaFrameShadow.String() != "frame+shadow" {
+		t.Error("unexpected window area names")
+	}
+	if WindowArea(99).String() != "unknown" {
+		t.Error("expected unknown for invalid window area")
+	}
+}
@@ -0,0 +1,57 @@
+package xcap
+
+import (
+	"sync/atomic"
+
+	"github.com/zn-chen/xcap/internal/errcode"
+)
+
+// nativeWindow 是平台窗口快照，由 darwin.Window、windows.Window 实现
+type nativeWindow interface {
+	ID() uint32
+	PID() uint32
+}
+
+// windowHandle 持有平台窗口的当前快照，Refresh 时整体替换
+// 快照通过原子操作读写，可以被多个 goroutine 同时使用
+type windowHandle[W nativeWindow] struct {
+	cur atomic.Value
+}
+
+func newWindowHandle[W nativeWindow](w W) *windowHandle[W] {
+	h := &windowHandle[W]{}
+	h.cur.Store(w)
+	return h
+}
+
+// load 返回当前的快照
+func (h *windowHandle[W]) load() W {
+	return h.cur.Load().(W)
+}
+
+// query 用 fetch 重新读取当前快照对应的窗口
+// 窗口 ID 被其他进程的窗口复用时按窗口已关闭处理，返回 NotFound 错误
+func (h *windowHandle[W]) query(fetch func(W) (W, error)) (W, error) {
+	cur := h.load()
+	fresh, err := fetch(cur)
+	if err == nil && fresh.PID() != cur.PID() {
+		err = &errcode.Error{Op: "get window info", Code: errcode.NotFound}
+	}
+	return fresh, err
+}
+
+// refresh 是 Window.Refresh 的通用实现：查询成功时替换快照，失败时保留原有的快照
+func (h *windowHandle[W]) refresh(fetch func(W) (W, error)) error {
+	fresh, err := h.query(fetch)
+	if err != nil {
+		return windowError("refresh window", h.load().ID(), err)
+	}
+	h.cur.Store(fresh)
+	return nil
+}
+
+// exists 是 Window.Exists 的通用实现
+func (h *windowHandle[W]) exists(fetch func(W) (W, error)) (bool, error) {
+	_, err := h.query(fetch)
+	return windowExists(windowError("check window exists", h.load().ID(), err))
+}
@@ -0,0 +1,150 @@
+package xcap
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zn-chen/xcap/internal/errcode"
+)
+
+func TestWindowByID(t *testing.T) {
+	a := &fakeWindow{id: 10, title: "a", z: 2}
+	b := &fakeWindow{id: 20, title: "b", z: 1}
+	tool := &fakeWindow{id: 40, title: "tool"}
+	useFakeBackend(t, &fakeBackend{windows: []Window{a, b}, hidden: []Window{tool}})
+
+	w, err := WindowByID(20)
+	if err != nil {
+		t.Fatalf("WindowByID failed: %v", err)
+	}
+	if w.Title() != "b" {
+		t.Errorf("got %q", w.Title())
+	}
+
+	// 不在枚举结果中的窗口也能找到
+	if w, err := WindowByID(40); err != nil || w.Title() != "tool" {
+		t.Errorf("WindowByID(filtered window) = %v, %v", w, err)
+	}
+
+	if _, err := WindowByID(30); !errors.Is(err, ErrWindowClosed) || !errors.Is(err, ErrNoWindow) {
+		t.Errorf("expected ErrWindowClosed, got %v", err)
+	}
+}
+
+// nativeFake 是测试用的平台窗口快照
+type nativeFake struct {
+	id, pid uint32
+	title   string
+}
+
+func (w *nativeFake) ID() uint32  { return w.id }
+func (w *nativeFake) PID() uint32 { return w.pid }
+
+func TestWindowHandle(t *testing.T) {
+	h := newWindowHandle(&nativeFake{id: 7, pid: 100, title: "old"})
+
+	var next *nativeFake
+	var nextErr error
+	fetch := func(cur *nativeFake) (*nativeFake, error) {
+		if cur.id != 7 {
+			t.Errorf("fetch called with window %d", cur.id)
+		}
+		return next, nextErr
+	}
+
+	// 查询成功时替换快照
+	next = &nativeFake{id: 7, pid: 100, title: "new"}
+	if err := h.refresh(fetch); err != nil {
+		t.Fatalf("refresh failed: %v", err)
+	}
+	if h.load().title != "new" {
+		t.Errorf("title = %q after refresh", h.load().title)
+	}
+	if ok, err := h.exists(fetch); !ok || err != nil {
+		t.Errorf("exists = %v, %v", ok, err)
+	}
+
+	// ID 被其他进程复用时按已关闭处理，保留原有的快照
+	next = &nativeFake{id: 7, pid: 200, title: "reused"}
+	if err := h.refresh(fetch); !errors.Is(err, ErrWindowClosed) {
+		t.Errorf("refresh after PID change = %v, want ErrWindowClosed", err)
+	}
+	if ok, err := h.exists(fetch); ok || err != nil {
+		t.Errorf("exists after PID change = %v, %v", ok, err)
+	}
+	if h.load().title != "new" {
+		t.Errorf("snapshot replaced by reused window: %q", h.load().title)
+	}
+
+	// 窗口已关闭
+	next, nextErr = nil, &errcode.Error{Op: "get window info", Code: errcode.NotFound}
+	if err := h.refresh(fetch); !errors.Is(err, ErrWindowClosed) {
+		t.Errorf("refresh on closed window = %v, want ErrWindowClosed", err)
+	}
+	if ok, err := h.exists(fetch); ok || err != nil {
+		t.Errorf("exists on closed window = %v, %v", ok, err)
+	}
+	if h.load().title != "new" {
+		t.Errorf("snapshot replaced after close: %q", h.load().title)
+	}
+
+	// 其他错误原样报告
+	nextErr = &errcode.Error{Op: "get window info", Code: errcode.CaptureFailed}
+	if err := h.refresh(fetch); err == nil || errors.Is(err, ErrWindowClosed) {
+		t.Errorf("refresh with backend failure = %v", err)
+	}
+	if ok, err := h.exists(fetch); ok || err == nil {
+		t.Errorf("exists with backend failure = %v, %v", ok, err)
+	}
+}
+
+func TestWindowRefresh(t *testing.T) {
+	w := &fakeWindow{id: 1, title: "old", x: 10, width: 100, height: 50, z: 4}
+	w.refreshed = &fakeWindow{title: "new", x: 200, width: 300, height: 150}
+
+	if err := w.Refresh(); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if w.Title() != "new" || w.X() != 200 || w.Width() != 300 || w.Z() != 4 {
+		t.Errorf("after refresh: %q x=%d w=%d z=%d", w.Title(), w.X(), w.Width(), w.Z())
+	}
+
+	ok, err := w.Exists()
+	if !ok || err != nil {
+		t.Errorf("Exists = %v, %v", ok, err)
+	}
+
+	// 关闭后 Refresh 返回 ErrWindowClosed，保留原有的值
+	w.closed = true
+	if err := w.Refresh(); !errors.Is(err, ErrWindowClosed) || !errors.Is(err, ErrNoWindow) {
+		t.Errorf("Refresh on closed window = %v", err)
+	}
+	if w.Title() != "new" {
+		t.Errorf("title changed to %q", w.Title())
+	}
+	if ok, err := w.Exists(); ok || err != nil {
+		t.Errorf("Exists on closed window = %v, %v", ok, err)
+	}
+	if _, err := CaptureWindowWithOptions(w, CaptureOptions{}); !errors.Is(err, ErrWindowClosed) {
+		t.Errorf("capture closed window = %v", err)
+	}
+}
+
+func TestWindowExists(t *testing.T) {
+	other := windowError("check window exists", 3, errors.New("boom"))
+	if ok, err := windowExists(other); ok || err != other {
+		t.Errorf("windowExists(other) = %v, %v", ok, err)
+	}
+	if ok, err := windowExists(nil); !ok || err != nil {
+		t.Errorf("windowExists(nil) = %v, %v", ok, err)
+	}
+}
+
+func TestWindowTypeString(t *testing.T) {
+	if WindowTypeDialog.String() != "dialog" || WindowTypeTooltip.String() != "tooltip" {
+		t.Error("unexpected window type names")
+	}
+	if WindowType(99).String() != "unknown" {
+		t.Error("expected unknown for invalid window type")
+	}
+}
@@ -0,0 +1,48 @@
+package xcap
+
+// WindowType 表示窗口的用途，用于区分主窗口和对话框、工具提示、面板等辅助窗口
+//
+// 平台没有直接提供窗口类型，由窗口类、样式（Windows）或窗口层级（macOS）推断，
+// 无法判断时为 WindowTypeUnknown。
+type WindowType int
+
+const (
+	// WindowTypeUnknown 类型未知
+	WindowTypeUnknown WindowType = iota
+	// WindowTypeNormal 普通的应用程序窗口
+	WindowTypeNormal
+	// WindowTypeDialog 对话框
+	WindowTypeDialog
+	// WindowTypeUtility 工具窗口或浮动面板
+	WindowTypeUtility
+	// WindowTypeMenu 弹出菜单
+	WindowTypeMenu
+	// WindowTypeTooltip 工具提示
+	WindowTypeTooltip
+	// WindowTypeDock 任务栏、菜单栏、Dock 等停靠在屏幕边缘的窗口
+	WindowTypeDock
+	// WindowTypeDesktop 桌面背景和桌面图标
+	WindowTypeDesktop
+)
+
+// String 返回窗口类型的名称
+func (t WindowType) String() string {
+	switch t {
+	case WindowTypeNormal:
+		return "normal"
+	case WindowTypeDialog:
+		return "dialog"
+	case WindowTypeUtility:
+		return "utility"
+	case WindowTypeMenu:
+		return "menu"
+	case WindowTypeTooltip:
+		return "tooltip"
+	case WindowTypeDock:
+		return "dock"
+	case WindowTypeDesktop:
+		return "desktop"
+	default:
+		return "unknown"
+	}
+}
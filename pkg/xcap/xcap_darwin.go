@@ -3,73 +3,323 @@
 package xcap
 
 import (
+	"context"
 	"image"
+	"image/color"
+	"math"
 
 	"github.com/zn-chen/xcap/internal/darwin"
+	"github.com/zn-chen/xcap/internal/worker"
 )
 
+// nativeThread 串行执行所有原生调用，见 doc.go 中的并发说明
+// ID、坐标、缩放因子等属性 getter 不依赖线程状态，直接调用
+var nativeThread worker.Worker
+
+// monitorWrapper 包装 darwin.Monitor 以实现 xcap.Monitor 接口
+type monitorWrapper struct {
+	m *darwin.Monitor
+}
+
+func (m *monitorWrapper) ID() uint32           { return m.m.ID() }
+func (m *monitorWrapper) Name() string         { return m.m.Name() }
+func (m *monitorWrapper) X() int               { return m.m.X() }
+func (m *monitorWrapper) Y() int               { return m.m.Y() }
+func (m *monitorWrapper) Width() uint32        { return m.m.Width() }
+func (m *monitorWrapper) Height() uint32       { return m.m.Height() }
+func (m *monitorWrapper) Rotation() float32    { return m.m.Rotation() }
+func (m *monitorWrapper) ScaleFactor() float32 { return m.m.ScaleFactor() }
+func (m *monitorWrapper) Frequency() float32   { return m.m.Frequency() }
+func (m *monitorWrapper) IsPrimary() bool      { return m.m.IsPrimary() }
+func (m *monitorWrapper) IsBuiltin() bool      { return m.m.IsBuiltin() }
+
+func (m *monitorWrapper) CaptureImage() (*image.RGBA, error) {
+	return m.CaptureImageContext(context.Background())
+}
+
+func (m *monitorWrapper) CaptureImageContext(ctx context.Context) (*image.RGBA, error) {
+	img, err := worker.CallContext(ctx, &nativeThread, m.m.CaptureImage)
+	return img, monitorError("capture monitor", m.ID(), err)
+}
+
+func (m *monitorWrapper) CaptureRegion(x, y, width, height uint32) (*image.RGBA, error) {
+	img, err := worker.Call(&nativeThread, func() (*image.RGBA, error) {
+		return m.m.CaptureRegion(x, y, width, height)
+	})
+	return img, monitorError("capture monitor region", m.ID(), err)
+}
+
+func (m *monitorWrapper) PixelAt(x, y int) (color.RGBA, error) {
+	return monitorPixelAt(m, x, y)
+}
+
+func (m *monitorWrapper) StableID() string {
+	return monitorStableID(m)
+}
+
+func (m *monitorWrapper) Info() (*MonitorInfo, error) {
+	d, err := worker.Call(&nativeThread, m.m.Details)
+	if err != nil {
+		return nil, monitorError("get monitor info", m.ID(), err)
+	}
+
+	// 系统不提供外接显示器的接口类型，只能区分内置面板
+	connector := ConnectorUnknown
+	if m.IsBuiltin() {
+		connector = ConnectorInternal
+	}
+
+	return newMonitorInfo(nativeMonitorInfo{
+		name:         m.Name(),
+		edid:         d.EDID,
+		vendor:       uint16(d.Vendor),
+		product:      uint16(d.Model),
+		serial:       d.Serial,
+		widthMM:      int(math.Round(float64(d.WidthMM))),
+		heightMM:     int(math.Round(float64(d.HeightMM))),
+		connector:    connector,
+		bitsPerColor: d.BitsPerSample,
+	}), nil
+}
+
+func (m *monitorWrapper) captureExcluding(windowIDs []uint32) (*image.RGBA, error) {
+	img, err := worker.Call(&nativeThread, func() (*image.RGBA, error) {
+		return m.m.CaptureExcluding(windowIDs)
+	})
+	return img, monitorError("capture monitor excluding windows", m.ID(), err)
+}
+
 // windowWrapper 包装 darwin.Window 以实现 xcap.Window 接口
 type windowWrapper struct {
-	w *darwin.Window
-}
-
-func (w *windowWrapper) ID() uint32        { return w.w.ID() }
-func (w *windowWrapper) PID() uint32       { return w.w.PID() }
-func (w *windowWrapper) AppName() string   { return w.w.AppName() }
-func (w *windowWrapper) Title() string     { return w.w.Title() }
-func (w *windowWrapper) X() int            { return w.w.X() }
-func (w *windowWrapper) Y() int            { return w.w.Y() }
-func (w *windowWrapper) Z() int            { return w.w.Z() }
-func (w *windowWrapper) Width() uint32     { return w.w.Width() }
-func (w *windowWrapper) Height() uint32    { return w.w.Height() }
-func (w *windowWrapper) IsMinimized() (bool, error) { return w.w.IsMinimized() }
-func (w *windowWrapper) IsMaximized() (bool, error) { return w.w.IsMaximized() }
-func (w *windowWrapper) IsFocused() (bool, error)   { return w.w.IsFocused() }
+	h *windowHandle[*darwin.Window]
+}
+
+func newWindowWrapper(w *darwin.Window) *windowWrapper {
+	return &windowWrapper{h: newWindowHandle(w)}
+}
+
+// win 返回当前的窗口快照
+func (w *windowWrapper) win() *darwin.Window { return w.h.load() }
+
+func (w *windowWrapper) ID() uint32        { return w.win().ID() }
+func (w *windowWrapper) PID() uint32       { return w.win().PID() }
+func (w *windowWrapper) AppName() string   { return w.win().AppName() }
+func (w *windowWrapper) Title() string     { return w.win().Title() }
+func (w *windowWrapper) X() int            { return w.win().X() }
+func (w *windowWrapper) Y() int            { return w.win().Y() }
+func (w *windowWrapper) Z() int            { return w.win().Z() }
+func (w *windowWrapper) Width() uint32     { return w.win().Width() }
+func (w *windowWrapper) Height() uint32    { return w.win().Height() }
+
+func (w *windowWrapper) Refresh() error {
+	return w.h.refresh(refreshNative)
+}
+
+func (w *windowWrapper) Exists() (bool, error) {
+	return w.h.exists(refreshNative)
+}
+
+// refreshNative 在工作线程上重新读取窗口信息
+func refreshNative(w *darwin.Window) (*darwin.Window, error) {
+	return worker.Call(&nativeThread, w.Refresh)
+}
+
+func (w *windowWrapper) IsMinimized() (bool, error) {
+	v, err := worker.Call(&nativeThread, w.win().IsMinimized)
+	return v, windowError("check minimized", w.ID(), err)
+}
+
+func (w *windowWrapper) IsMaximized() (bool, error) {
+	v, err := worker.Call(&nativeThread, w.win().IsMaximized)
+	return v, windowError("check maximized", w.ID(), err)
+}
+
+func (w *windowWrapper) IsFocused() (bool, error) {
+	v, err := worker.Call(&nativeThread, w.win().IsFocused)
+	return v, windowError("check focused", w.ID(), err)
+}
+
+func (w *windowWrapper) Class() (string, error) {
+	v, err := worker.Call(&nativeThread, w.win().Class)
+	return v, windowError("get window class", w.ID(), err)
+}
+
+func (w *windowWrapper) Type() (WindowType, error) {
+	v, err := worker.Call(&nativeThread, w.win().Type)
+	if err != nil {
+		return WindowTypeUnknown, windowError("get window type", w.ID(), err)
+	}
+	return windowTypeFromNative(v), nil
+}
+
+func (w *windowWrapper) ProcessPath() (string, error) {
+	v, err := worker.Call(&nativeThread, w.win().ProcessPath)
+	return v, windowError("get process path", w.ID(), err)
+}
+
+func (w *windowWrapper) OwnerID() (uint32, error) {
+	v, err := worker.Call(&nativeThread, w.win().OwnerID)
+	return v, windowError("get window owner", w.ID(), err)
+}
+
+func (w *windowWrapper) ParentID() (uint32, error) {
+	v, err := worker.Call(&nativeThread, w.win().ParentID)
+	return v, windowError("get window parent", w.ID(), err)
+}
+
+func (w *windowWrapper) IsModal() (bool, error) {
+	v, err := worker.Call(&nativeThread, w.win().IsModal)
+	return v, windowError("check modal", w.ID(), err)
+}
+
+func (w *windowWrapper) Opacity() (float64, error) {
+	v, err := worker.Call(&nativeThread, w.win().Opacity)
+	return v, windowError("get window opacity", w.ID(), err)
+}
+
+// windowTypeFromNative 把 darwin.WindowType* 转换为 WindowType
+func windowTypeFromNative(t int) WindowType {
+	switch t {
+	case darwin.WindowTypeNormal:
+		return WindowTypeNormal
+	case darwin.WindowTypeDialog:
+		return WindowTypeDialog
+	case darwin.WindowTypeUtility:
+		return WindowTypeUtility
+	case darwin.WindowTypeMenu:
+		return WindowTypeMenu
+	case darwin.WindowTypeTooltip:
+		return WindowTypeTooltip
+	case darwin.WindowTypeDock:
+		return WindowTypeDock
+	case darwin.WindowTypeDesktop:
+		return WindowTypeDesktop
+	default:
+		return WindowTypeUnknown
+	}
+}
 
 func (w *windowWrapper) CurrentMonitor() (Monitor, error) {
-	m, err := w.w.CurrentMonitor()
+	m, err := worker.Call(&nativeThread, w.win().CurrentMonitor)
 	if err != nil {
-		return nil, err
+		return nil, windowError("get current monitor", w.ID(), err)
 	}
-	return m, nil
+	return &monitorWrapper{m: m}, nil
 }
 
 func (w *windowWrapper) CaptureImage() (*image.RGBA, error) {
-	return w.w.CaptureImage()
+	return w.CaptureImageContext(context.Background())
+}
+
+func (w *windowWrapper) CaptureImageContext(ctx context.Context) (*image.RGBA, error) {
+	img, err := worker.CallContext(ctx, &nativeThread, w.win().CaptureImage)
+	return img, windowError("capture window", w.ID(), err)
+}
+
+// Bounds 只支持窗口框架，窗口服务器不提供标题栏高度和阴影范围
+func (w *windowWrapper) Bounds(area WindowArea) (image.Rectangle, error) {
+	if area != WindowAreaFrame {
+		return image.Rectangle{}, ErrNotSupported
+	}
+	r, err := worker.Call(&nativeThread, w.win().Bounds)
+	return r, windowError("get window bounds", w.ID(), err)
+}
+
+func (w *windowWrapper) captureArea(ctx context.Context, area WindowArea) (*image.RGBA, error) {
+	switch area {
+	case WindowAreaFrame:
+		return w.CaptureImageContext(ctx)
+	case WindowAreaFrameShadow:
+		img, err := worker.CallContext(ctx, &nativeThread, w.win().CaptureImageWithShadow)
+		return img, windowError("capture window", w.ID(), err)
+	default:
+		return nil, ErrNotSupported
+	}
+}
+
+func (w *windowWrapper) VisibleRegion() ([]image.Rectangle, error) {
+	return windowVisibleRegion(w)
 }
 
-// AllMonitors 返回系统上所有可用的显示器
-func AllMonitors() ([]Monitor, error) {
-	monitors, err := darwin.AllMonitors()
+func (w *windowWrapper) IsOccluded() (bool, error) {
+	return windowIsOccluded(w)
+}
+
+// nativeBackend 基于 darwin 包实现 backend
+type nativeBackend struct{}
+
+func (nativeBackend) allMonitors(ctx context.Context) ([]Monitor, error) {
+	monitors, err := worker.CallContext(ctx, &nativeThread, darwin.AllMonitors)
 	if err != nil {
-		return nil, err
+		return nil, monitorError("get monitors", 0, err)
 	}
 
 	result := make([]Monitor, len(monitors))
 	for i, m := range monitors {
-		result[i] = m
+		result[i] = &monitorWrapper{m: m}
 	}
 
 	return result, nil
 }
 
-// AllWindows 返回系统上所有可见的窗口（包括当前进程的窗口）
-func AllWindows() ([]Window, error) {
-	return AllWindowsWithOptions(false)
-}
-
-// AllWindowsWithOptions 返回系统上所有可见的窗口
-// excludeCurrentProcess: 是否排除当前进程的窗口
-func AllWindowsWithOptions(excludeCurrentProcess bool) ([]Window, error) {
-	windows, err := darwin.AllWindowsWithOptions(excludeCurrentProcess)
+func (nativeBackend) allWindows(ctx context.Context, excludeCurrentProcess bool) ([]Window, error) {
+	windows, err := worker.CallContext(ctx, &nativeThread, func() ([]*darwin.Window, error) {
+		return darwin.AllWindowsWithOptions(excludeCurrentProcess)
+	})
 	if err != nil {
-		return nil, err
+		return nil, windowError("get windows", 0, err)
 	}
 
 	result := make([]Window, len(windows))
 	for i, w := range windows {
-		result[i] = &windowWrapper{w: w}
+		result[i] = newWindowWrapper(w)
 	}
 
 	return result, nil
 }
+
+func (nativeBackend) windowByID(id uint32) (Window, error) {
+	w, err := worker.Call(&nativeThread, func() (*darwin.Window, error) {
+		return darwin.WindowByID(id)
+	})
+	if err != nil {
+		return nil, windowError("get window", id, err)
+	}
+	return newWindowWrapper(w), nil
+}
+
+func (nativeBackend) checkPermission() PermissionStatus {
+	var granted bool
+	nativeThread.Do(func() { granted = darwin.CheckScreenCaptureAccess() })
+	if granted {
+		return PermissionGranted
+	}
+	return PermissionDenied
+}
+
+func (nativeBackend) requestPermission() PermissionStatus {
+	var granted bool
+	nativeThread.Do(func() { granted = darwin.RequestScreenCaptureAccess() })
+	if granted {
+		return PermissionGranted
+	}
+	return PermissionDenied
+}
+
+func (nativeBackend) cursorPosition() (image.Point, error) {
+	p, err := worker.Call(&nativeThread, darwin.CursorPosition)
+	return p, monitorError("get cursor position", 0, err)
+}
+
+func (nativeBackend) cursorImage() (*Cursor, error) {
+	c, err := worker.Call(&nativeThread, darwin.CursorImage)
+	if err != nil {
+		return nil, monitorError("capture cursor", 0, err)
+	}
+	return &Cursor{Image: c.Image, Hotspot: c.Hotspot, Scale: float64(c.Scale), Visible: c.Visible}, nil
+}
+
+// macOS 的显示器和窗口几何使用点（Quartz 全局显示坐标）
+func (nativeBackend) coordinateSpace() Space {
+	return SpaceLogical
+}
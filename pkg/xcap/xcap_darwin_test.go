@@ -0,0 +1,30 @@
+//go:build darwin
+
+package xcap
+
+import (
+	"testing"
+
+	"github.com/zn-chen/xcap/internal/darwin"
+)
+
+func TestWindowTypeFromNative(t *testing.T) {
+	for _, c := range []struct {
+		native int
+		want   WindowType
+	}{
+		{darwin.WindowTypeUnknown, WindowTypeUnknown},
+		{darwin.WindowTypeNormal, WindowTypeNormal},
+		{darwin.WindowTypeDialog, WindowTypeDialog},
+		{darwin.WindowTypeUtility, WindowTypeUtility},
+		{darwin.WindowTypeMenu, WindowTypeMenu},
+		{darwin.WindowTypeTooltip, WindowTypeTooltip},
+		{darwin.WindowTypeDock, WindowTypeDock},
+		{darwin.WindowTypeDesktop, WindowTypeDesktop},
+		{99, WindowTypeUnknown},
+	} {
+		if got := windowTypeFromNative(c.native); got != c.want {
+			t.Errorf("windowTypeFromNative(%d) = %v, want %v", c.native, got, c.want)
+		}
+	}
+}
@@ -2,18 +2,42 @@
 
 package xcap
 
-// AllMonitors 返回系统上所有可用的显示器
-func AllMonitors() ([]Monitor, error) {
+import (
+	"context"
+	"image"
+)
+
+// nativeBackend 在不支持的平台上总是返回 ErrNotSupported
+type nativeBackend struct{}
+
+func (nativeBackend) allMonitors(ctx context.Context) ([]Monitor, error) {
 	return nil, ErrNotSupported
 }
 
-// AllWindows 返回系统上所有可见的窗口（包括当前进程的窗口）
-func AllWindows() ([]Window, error) {
+func (nativeBackend) allWindows(ctx context.Context, excludeCurrentProcess bool) ([]Window, error) {
 	return nil, ErrNotSupported
 }
 
-// AllWindowsWithOptions 返回系统上所有可见的窗口
-// excludeCurrentProcess: 是否排除当前进程的窗口
-func AllWindowsWithOptions(excludeCurrentProcess bool) ([]Window, error) {
+func (nativeBackend) windowByID(id uint32) (Window, error) {
 	return nil, ErrNotSupported
 }
+
+func (nativeBackend) checkPermission() PermissionStatus {
+	return PermissionUnknown
+}
+
+func (nativeBackend) requestPermission() PermissionStatus {
+	return PermissionUnknown
+}
+
+func (nativeBackend) cursorPosition() (image.Point, error) {
+	return image.Point{}, ErrNotSupported
+}
+
+func (nativeBackend) cursorImage() (*Cursor, error) {
+	return nil, ErrNotSupported
+}
+
+func (nativeBackend) coordinateSpace() Space {
+	return SpaceLogical
+}
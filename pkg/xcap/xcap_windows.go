@@ -3,73 +3,357 @@
 package xcap
 
 import (
+	"context"
 	"image"
+	"image/color"
 
 	"github.com/zn-chen/xcap/internal/windows"
+	"github.com/zn-chen/xcap/internal/worker"
 )
 
+// nativeThread 串行执行所有原生调用，见 doc.go 中的并发说明
+// ID、坐标、缩放因子等属性 getter 不依赖线程状态，直接调用
+var nativeThread worker.Worker
+
+// monitorWrapper 包装 windows.Monitor 以实现 xcap.Monitor 接口
+type monitorWrapper struct {
+	m *windows.Monitor
+}
+
+func (m *monitorWrapper) ID() uint32           { return m.m.ID() }
+func (m *monitorWrapper) Name() string         { return m.m.Name() }
+func (m *monitorWrapper) X() int               { return m.m.X() }
+func (m *monitorWrapper) Y() int               { return m.m.Y() }
+func (m *monitorWrapper) Width() uint32        { return m.m.Width() }
+func (m *monitorWrapper) Height() uint32       { return m.m.Height() }
+func (m *monitorWrapper) Rotation() float32    { return m.m.Rotation() }
+func (m *monitorWrapper) ScaleFactor() float32 { return m.m.ScaleFactor() }
+func (m *monitorWrapper) Frequency() float32   { return m.m.Frequency() }
+func (m *monitorWrapper) IsPrimary() bool      { return m.m.IsPrimary() }
+func (m *monitorWrapper) IsBuiltin() bool      { return m.m.IsBuiltin() }
+
+func (m *monitorWrapper) CaptureImage() (*image.RGBA, error) {
+	return m.CaptureImageContext(context.Background())
+}
+
+func (m *monitorWrapper) CaptureImageContext(ctx context.Context) (*image.RGBA, error) {
+	img, err := worker.CallContext(ctx, &nativeThread, m.m.CaptureImage)
+	return img, monitorError("capture monitor", m.ID(), err)
+}
+
+func (m *monitorWrapper) CaptureRegion(x, y, width, height uint32) (*image.RGBA, error) {
+	img, err := worker.Call(&nativeThread, func() (*image.RGBA, error) {
+		return m.m.CaptureRegion(x, y, width, height)
+	})
+	return img, monitorError("capture monitor region", m.ID(), err)
+}
+
+func (m *monitorWrapper) PixelAt(x, y int) (color.RGBA, error) {
+	return monitorPixelAt(m, x, y)
+}
+
+func (m *monitorWrapper) StableID() string {
+	return monitorStableID(m)
+}
+
+func (m *monitorWrapper) Info() (*MonitorInfo, error) {
+	edid, err := worker.Call(&nativeThread, m.m.EDID)
+	if err != nil {
+		return nil, monitorError("get monitor info", m.ID(), err)
+	}
+
+	name := m.m.Model()
+	if name == "" {
+		name = m.Name()
+	}
+	// 32 位色深包含 8 位 alpha/填充
+	bits := int(min(m.m.BitsPerPixel(), 24)) / 3
+
+	return newMonitorInfo(nativeMonitorInfo{
+		name:         name,
+		edid:         edid,
+		connector:    connectorFromNative(m.m.Connector()),
+		bitsPerColor: bits,
+	}), nil
+}
+
+// connectorFromNative 把 windows.Connector* 转换为 Connector
+func connectorFromNative(c int) Connector {
+	switch c {
+	case windows.ConnectorInternal:
+		return ConnectorInternal
+	case windows.ConnectorVGA:
+		return ConnectorVGA
+	case windows.ConnectorDVI:
+		return ConnectorDVI
+	case windows.ConnectorHDMI:
+		return ConnectorHDMI
+	case windows.ConnectorDisplayPort:
+		return ConnectorDisplayPort
+	case windows.ConnectorWireless:
+		return ConnectorWireless
+	case windows.ConnectorVirtual:
+		return ConnectorVirtual
+	default:
+		return ConnectorUnknown
+	}
+}
+
 // windowWrapper 包装 windows.Window 以实现 xcap.Window 接口
 type windowWrapper struct {
-	w *windows.Window
-}
-
-func (w *windowWrapper) ID() uint32        { return w.w.ID() }
-func (w *windowWrapper) PID() uint32       { return w.w.PID() }
-func (w *windowWrapper) AppName() string   { return w.w.AppName() }
-func (w *windowWrapper) Title() string     { return w.w.Title() }
-func (w *windowWrapper) X() int            { return w.w.X() }
-func (w *windowWrapper) Y() int            { return w.w.Y() }
-func (w *windowWrapper) Z() int            { return w.w.Z() }
-func (w *windowWrapper) Width() uint32     { return w.w.Width() }
-func (w *windowWrapper) Height() uint32    { return w.w.Height() }
-func (w *windowWrapper) IsMinimized() (bool, error) { return w.w.IsMinimized() }
-func (w *windowWrapper) IsMaximized() (bool, error) { return w.w.IsMaximized() }
-func (w *windowWrapper) IsFocused() (bool, error)   { return w.w.IsFocused() }
+	h *windowHandle[*windows.Window]
+}
+
+func newWindowWrapper(w *windows.Window) *windowWrapper {
+	return &windowWrapper{h: newWindowHandle(w)}
+}
+
+// win 返回当前的窗口快照
+func (w *windowWrapper) win() *windows.Window { return w.h.load() }
+
+func (w *windowWrapper) ID() uint32        { return w.win().ID() }
+func (w *windowWrapper) PID() uint32       { return w.win().PID() }
+func (w *windowWrapper) AppName() string   { return w.win().AppName() }
+func (w *windowWrapper) Title() string     { return w.win().Title() }
+func (w *windowWrapper) X() int            { return w.win().X() }
+func (w *windowWrapper) Y() int            { return w.win().Y() }
+func (w *windowWrapper) Z() int            { return w.win().Z() }
+func (w *windowWrapper) Width() uint32     { return w.win().Width() }
+func (w *windowWrapper) Height() uint32    { return w.win().Height() }
+
+func (w *windowWrapper) Refresh() error {
+	return w.h.refresh(refreshNative)
+}
+
+func (w *windowWrapper) Exists() (bool, error) {
+	return w.h.exists(refreshNative)
+}
+
+// refreshNative 在工作线程上重新读取窗口信息
+func refreshNative(w *windows.Window) (*windows.Window, error) {
+	return worker.Call(&nativeThread, w.Refresh)
+}
+
+func (w *windowWrapper) IsMinimized() (bool, error) {
+	v, err := worker.Call(&nativeThread, w.win().IsMinimized)
+	return v, windowError("check minimized", w.ID(), err)
+}
+
+func (w *windowWrapper) IsMaximized() (bool, error) {
+	v, err := worker.Call(&nativeThread, w.win().IsMaximized)
+	return v, windowError("check maximized", w.ID(), err)
+}
+
+func (w *windowWrapper) IsFocused() (bool, error) {
+	v, err := worker.Call(&nativeThread, w.win().IsFocused)
+	return v, windowError("check focused", w.ID(), err)
+}
+
+func (w *windowWrapper) Class() (string, error) {
+	v, err := worker.Call(&nativeThread, w.win().Class)
+	return v, windowError("get window class", w.ID(), err)
+}
+
+func (w *windowWrapper) Type() (WindowType, error) {
+	v, err := worker.Call(&nativeThread, w.win().Type)
+	if err != nil {
+		return WindowTypeUnknown, windowError("get window type", w.ID(), err)
+	}
+	return windowTypeFromNative(v), nil
+}
+
+func (w *windowWrapper) ProcessPath() (string, error) {
+	v, err := worker.Call(&nativeThread, w.win().ProcessPath)
+	return v, windowError("get process path", w.ID(), err)
+}
+
+func (w *windowWrapper) OwnerID() (uint32, error) {
+	v, err := worker.Call(&nativeThread, w.win().OwnerID)
+	return v, windowError("get window owner", w.ID(), err)
+}
+
+func (w *windowWrapper) ParentID() (uint32, error) {
+	v, err := worker.Call(&nativeThread, w.win().ParentID)
+	return v, windowError("get window parent", w.ID(), err)
+}
+
+func (w *windowWrapper) IsModal() (bool, error) {
+	v, err := worker.Call(&nativeThread, w.win().IsModal)
+	return v, windowError("check modal", w.ID(), err)
+}
+
+func (w *windowWrapper) Opacity() (float64, error) {
+	v, err := worker.Call(&nativeThread, w.win().Opacity)
+	return v, windowError("get window opacity", w.ID(), err)
+}
+
+// windowTypeFromNative 把 windows.WindowType* 转换为 WindowType
+func windowTypeFromNative(t int) WindowType {
+	switch t {
+	case windows.WindowTypeNormal:
+		return WindowTypeNormal
+	case windows.WindowTypeDialog:
+		return WindowTypeDialog
+	case windows.WindowTypeUtility:
+		return WindowTypeUtility
+	case windows.WindowTypeMenu:
+		return WindowTypeMenu
+	case windows.WindowTypeTooltip:
+		return WindowTypeTooltip
+	case windows.WindowTypeDock:
+		return WindowTypeDock
+	case windows.WindowTypeDesktop:
+		return WindowTypeDesktop
+	default:
+		return WindowTypeUnknown
+	}
+}
 
 func (w *windowWrapper) CurrentMonitor() (Monitor, error) {
-	m, err := w.w.CurrentMonitor()
+	m, err := worker.Call(&nativeThread, w.win().CurrentMonitor)
 	if err != nil {
-		return nil, err
+		return nil, windowError("get current monitor", w.ID(), err)
 	}
-	return m, nil
+	return &monitorWrapper{m: m}, nil
 }
 
+func (w *windowWrapper) Bounds(area WindowArea) (image.Rectangle, error) {
+	b, err := worker.Call(&nativeThread, w.win().Bounds)
+	if err != nil {
+		return image.Rectangle{}, windowError("get window bounds", w.ID(), err)
+	}
+	return windowAreaRect(b, area)
+}
+
+// CaptureImage 只返回窗口框架，与 X/Y/Width/Height 一致
+// PrintWindow 截取的窗口矩形还包括不可见的缩放边框
 func (w *windowWrapper) CaptureImage() (*image.RGBA, error) {
-	return w.w.CaptureImage()
+	return w.captureArea(context.Background(), WindowAreaFrame)
+}
+
+func (w *windowWrapper) CaptureImageContext(ctx context.Context) (*image.RGBA, error) {
+	return w.captureArea(ctx, WindowAreaFrame)
 }
 
-// AllMonitors 返回系统上所有可用的显示器
-func AllMonitors() ([]Monitor, error) {
-	monitors, err := windows.AllMonitors()
+// windowShot 是同一次平台调用中得到的窗口截图和各区域的位置
+type windowShot struct {
+	img    *image.RGBA
+	bounds windows.WindowBounds
+}
+
+func (w *windowWrapper) captureArea(ctx context.Context, area WindowArea) (*image.RGBA, error) {
+	if _, err := windowAreaRect(windows.WindowBounds{}, area); err != nil {
+		return nil, err
+	}
+
+	win := w.win()
+	shot, err := worker.CallContext(ctx, &nativeThread, func() (windowShot, error) {
+		b, err := win.Bounds()
+		if err != nil {
+			return windowShot{}, err
+		}
+		img, err := win.CaptureImage()
+		return windowShot{img: img, bounds: b}, err
+	})
+	if err != nil {
+		return nil, windowError("capture window", w.ID(), err)
+	}
+
+	r, err := windowAreaRect(shot.bounds, area)
 	if err != nil {
 		return nil, err
 	}
+	return cropWindowArea(shot.img, shot.bounds.Window, r)
+}
+
+// windowAreaRect 返回 b 中与 area 对应的区域
+// PrintWindow 不绘制阴影，WindowAreaFrameShadow 返回 ErrNotSupported
+func windowAreaRect(b windows.WindowBounds, area WindowArea) (image.Rectangle, error) {
+	switch area {
+	case WindowAreaFrame:
+		return b.Frame, nil
+	case WindowAreaClient:
+		return b.Client, nil
+	default:
+		return image.Rectangle{}, ErrNotSupported
+	}
+}
+
+func (w *windowWrapper) VisibleRegion() ([]image.Rectangle, error) {
+	return windowVisibleRegion(w)
+}
+
+func (w *windowWrapper) IsOccluded() (bool, error) {
+	return windowIsOccluded(w)
+}
+
+// nativeBackend 基于 windows 包实现 backend
+type nativeBackend struct{}
+
+func (nativeBackend) allMonitors(ctx context.Context) ([]Monitor, error) {
+	monitors, err := worker.CallContext(ctx, &nativeThread, windows.AllMonitors)
+	if err != nil {
+		return nil, monitorError("get monitors", 0, err)
+	}
 
 	result := make([]Monitor, len(monitors))
 	for i, m := range monitors {
-		result[i] = m
+		result[i] = &monitorWrapper{m: m}
 	}
 
 	return result, nil
 }
 
-// AllWindows 返回系统上所有可见的窗口（包括当前进程的窗口）
-func AllWindows() ([]Window, error) {
-	return AllWindowsWithOptions(false)
-}
-
-// AllWindowsWithOptions 返回系统上所有可见的窗口
-// excludeCurrentProcess: 是否排除当前进程的窗口
-func AllWindowsWithOptions(excludeCurrentProcess bool) ([]Window, error) {
-	wins, err := windows.AllWindowsWithOptions(excludeCurrentProcess)
+func (nativeBackend) allWindows(ctx context.Context, excludeCurrentProcess bool) ([]Window, error) {
+	wins, err := worker.CallContext(ctx, &nativeThread, func() ([]*windows.Window, error) {
+		return windows.AllWindowsWithOptions(excludeCurrentProcess)
+	})
 	if err != nil {
-		return nil, err
+		return nil, windowError("get windows", 0, err)
 	}
 
 	result := make([]Window, len(wins))
 	for i, w := range wins {
-		result[i] = &windowWrapper{w: w}
+		result[i] = newWindowWrapper(w)
 	}
 
 	return result, nil
 }
+
+func (nativeBackend) windowByID(id uint32) (Window, error) {
+	w, err := worker.Call(&nativeThread, func() (*windows.Window, error) {
+		return windows.WindowByID(id)
+	})
+	if err != nil {
+		return nil, windowError("get window", id, err)
+	}
+	return newWindowWrapper(w), nil
+}
+
+// Windows 上 GDI 截图不需要额外权限；安全桌面（UAC 提示、锁屏）激活时截图会返回 ErrPermissionDenied
+func (nativeBackend) checkPermission() PermissionStatus {
+	return PermissionGranted
+}
+
+func (nativeBackend) requestPermission() PermissionStatus {
+	return PermissionGranted
+}
+
+func (nativeBackend) cursorPosition() (image.Point, error) {
+	p, err := worker.Call(&nativeThread, windows.CursorPosition)
+	return p, monitorError("get cursor position", 0, err)
+}
+
+// Windows 上指针图像与屏幕截图同为物理像素，Scale 总是 1
+func (nativeBackend) cursorImage() (*Cursor, error) {
+	c, err := worker.Call(&nativeThread, windows.CursorImage)
+	if err != nil {
+		return nil, monitorError("capture cursor", 0, err)
+	}
+	return &Cursor{Image: c.Image, Hotspot: c.Hotspot, Scale: 1, Visible: c.Visible}, nil
+}
+
+// 进程启用了每显示器 DPI 感知，显示器和窗口几何均为物理像素
+func (nativeBackend) coordinateSpace() Space {
+	return SpacePhysical
+}
@@ -0,0 +1,30 @@
+//go:build windows
+
+package xcap
+
+import (
+	"testing"
+
+	"github.com/zn-chen/xcap/internal/windows"
+)
+
+func TestWindowTypeFromNative(t *testing.T) {
+	for _, c := range []struct {
+		native int
+		want   WindowType
+	}{
+		{windows.WindowTypeUnknown, WindowTypeUnknown},
+		{windows.WindowTypeNormal, WindowTypeNormal},
+		{windows.WindowTypeDialog, WindowTypeDialog},
+		{windows.WindowTypeUtility, WindowTypeUtility},
+		{windows.WindowTypeMenu, WindowTypeMenu},
+		{windows.WindowTypeTooltip, WindowTypeTooltip},
+		{windows.WindowTypeDock, WindowTypeDock},
+		{windows.WindowTypeDesktop, WindowTypeDesktop},
+		{99, WindowTypeUnknown},
+	} {
+		if got := windowTypeFromNative(c.native); got != c.want {
+			t.Errorf("windowTypeFromNative(%d) = %v, want %v", c.native, got, c.want)
+		}
+	}
+}